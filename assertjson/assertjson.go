@@ -0,0 +1,121 @@
+package assertjson
+
+import (
+	"fmt"
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/internal"
+	"github.com/go-clarum/clarum-json/recorder"
+	"strings"
+	"time"
+)
+
+// TestingT is the part of [testing.TB] used by the assertions, so that they also work with other test frameworks.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// Document is a JSON document given either as a string or as bytes, like the body of an HTTP response.
+type Document interface {
+	~string | ~[]byte
+}
+
+// Option configures the [comparator.Comparator] used by an assertion.
+// Any method of the [comparator.Builder] can be used inline:
+//
+//	assertjson.Equal(t, expected, actual, func(builder *comparator.Builder) *comparator.Builder {
+//		return builder.MaxDepth(10)
+//	})
+type Option func(builder *comparator.Builder) *comparator.Builder
+
+// StrictObjectCheck configures if the actual objects may have fields that are not expected,
+// see [comparator.Builder.StrictObjectCheck].
+func StrictObjectCheck(check bool) Option {
+	return func(builder *comparator.Builder) *comparator.Builder {
+		return builder.StrictObjectCheck(check)
+	}
+}
+
+// Recorder replaces the [recorder.DefaultRecorder] used for the failure message.
+// A nil recorder removes the recorder output from the message. Recorders keep their output,
+// so create a new one for every assertion.
+func Recorder(recorder recorder.Recorder) Option {
+	return func(builder *comparator.Builder) *comparator.Builder {
+		if recorder == nil {
+			return builder.Recorder(internal.NewNoopRecorder())
+		}
+		return builder.Recorder(recorder)
+	}
+}
+
+// StopAfter stops the comparison after the given number of mismatches, see [comparator.Builder.StopAfter].
+func StopAfter(mismatches int) Option {
+	return func(builder *comparator.Builder) *comparator.Builder {
+		return builder.StopAfter(mismatches)
+	}
+}
+
+// FailFast stops the comparison at the first mismatch, see [comparator.Builder.FailFast].
+func FailFast() Option {
+	return StopAfter(1)
+}
+
+// Equal checks that the actual JSON matches the expected one, with the same rules as [comparator.Comparator.Compare].
+// If not, the test is marked as failed with a single message that contains all mismatches and the recorder output.
+// It returns true if the JSONs match.
+func Equal[E, A Document](t TestingT, expected E, actual A, options ...Option) bool {
+	t.Helper()
+
+	start := time.Now()
+	recorderLog, err := build(options).Compare([]byte(expected), []byte(actual))
+	return check(t, "JSON does not match", start, recorderLog, err)
+}
+
+// Contains checks that the actual JSON contains the expected one: the actual objects may have fields
+// that are not expected. Otherwise, it behaves like [Equal].
+func Contains[E, A Document](t TestingT, expected E, actual A, options ...Option) bool {
+	t.Helper()
+
+	return Equal(t, expected, actual, append([]Option{StrictObjectCheck(false)}, options...)...)
+}
+
+// Matches checks that the values selected by the JSONPath in the actual JSON match the expected value,
+// see [comparator.Assertion]. The expected value can be any Go value, raw JSON or the '@ignore@' marker.
+//
+//	assertjson.Matches(t, body, "$.items.length()", 3)
+func Matches[A Document](t TestingT, actual A, path string, expected any, options ...Option) bool {
+	t.Helper()
+
+	start := time.Now()
+	recorderLog, err := build(options).Assert([]byte(actual), comparator.Assertion{Path: path, Expected: expected})
+	return check(t, fmt.Sprintf("JSON does not match at [%s]", path), start, recorderLog, err)
+}
+
+func build(options []Option) *comparator.Comparator {
+	builder := comparator.NewComparator().Recorder(recorder.NewDefaultRecorder())
+	for _, option := range options {
+		builder = option(builder)
+	}
+	return builder.Build()
+}
+
+// check collects the result of the assertion and fails the test with one message containing the errors and
+// the recorder output, so that they are printed together instead of being interleaved with the output of other tests.
+func check(t TestingT, title string, start time.Time, recorderLog string, err error) bool {
+	t.Helper()
+
+	collect(t, title, time.Since(start), recorderLog, err)
+	if err == nil {
+		return true
+	}
+
+	var message strings.Builder
+	message.WriteString(title + ":\n")
+	message.WriteString(err.Error())
+	if recorderLog != "" {
+		message.WriteString("\n\n" + strings.TrimSuffix(recorderLog, "\n"))
+	}
+
+	t.Errorf("%s", message.String())
+	return false
+}
@@ -0,0 +1,121 @@
+package assertjson
+
+import (
+	"fmt"
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/recorder"
+	"testing"
+)
+
+type fakeT struct {
+	name     string
+	helper   bool
+	failures []string
+}
+
+func (t *fakeT) Name() string {
+	return t.name
+}
+
+func (t *fakeT) Helper() {
+	t.helper = true
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestEqual(t *testing.T) {
+	fake := &fakeT{}
+
+	if !Equal(fake, "{\"name\": \"Bruce\", \"id\": \"@ignore@\"}", []byte("{\"id\": 7, \"name\": \"Bruce\"}")) {
+		t.Error("the JSONs must match")
+	}
+	checkFailures(t, fake, nil)
+}
+
+func TestEqualFailure(t *testing.T) {
+	fake := &fakeT{}
+
+	if Equal(fake, "{\"name\": \"Bruce\"}", "{\"name\": \"Dick\"}") {
+		t.Error("the JSONs must not match")
+	}
+	checkFailures(t, fake, []string{"JSON does not match:\n" +
+		"[$.name] - value mismatch - expected [Bruce] but received [Dick]\n\n" +
+		"{\n  \"name\": Dick, <-- value mismatch - expected [Bruce]\n}"})
+}
+
+func TestEqualAllFindingsInOneMessage(t *testing.T) {
+	fake := &fakeT{}
+
+	Equal(fake, "[1, 2, 3]", "[4, 5, 6]", Recorder(nil))
+
+	checkFailures(t, fake, []string{"JSON does not match:\n" +
+		"[$[0]] - value mismatch - expected [1] but received [4]\n" +
+		"[$[1]] - value mismatch - expected [2] but received [5]\n" +
+		"[$[2]] - value mismatch - expected [3] but received [6]"})
+}
+
+func TestEqualOptions(t *testing.T) {
+	fake := &fakeT{}
+
+	Equal(fake, "[1, 2, 3]", "[4, 5, 6]", FailFast(), Recorder(recorder.NewSideBySideRecorder()))
+	checkFailures(t, fake, []string{"JSON does not match:\n" +
+		"[$[0]] - value mismatch - expected [1] but received [4]\n" +
+		"comparison stopped after [1] mismatch(es) - [2] check(s) skipped\n\n" +
+		"[      [\n  1, |   4,\n]      ]\n" +
+		"... <-- comparison stopped after [1] mismatch(es) - [2] check(s) skipped"})
+
+	fake = &fakeT{}
+	Equal(fake, "[[1]]", "[[1]]", func(builder *comparator.Builder) *comparator.Builder {
+		return builder.MaxDepth(1)
+	})
+	checkFailures(t, fake, []string{"JSON does not match:\n" +
+		"[$[0]] - comparison stopped - maximum depth exceeded - limit is [1]\n\n[\n]"})
+}
+
+func TestContains(t *testing.T) {
+	fake := &fakeT{}
+
+	if !Contains(fake, "{\"name\": \"Bruce\"}", "{\"name\": \"Bruce\", \"age\": 37}") {
+		t.Error("extra fields must be allowed")
+	}
+	checkFailures(t, fake, nil)
+
+	if Contains(fake, "{\"name\": \"Bruce\", \"age\": 37}", "{\"name\": \"Bruce\"}", Recorder(nil)) {
+		t.Error("missing fields must not be allowed")
+	}
+	checkFailures(t, fake, []string{"JSON does not match:\n[$.age] - field is missing"})
+}
+
+func TestMatches(t *testing.T) {
+	fake := &fakeT{}
+	actual := []byte("{\"status\": \"OK\", \"items\": [{\"id\": 1}, {\"id\": 2}]}")
+
+	if !Matches(fake, actual, "$.items.length()", 2) || !Matches(fake, actual, "$.status", "OK") {
+		t.Error("the values must match")
+	}
+	checkFailures(t, fake, nil)
+
+	if Matches(fake, actual, "$.items[*].id", 1, Recorder(nil)) {
+		t.Error("the second item must not match")
+	}
+	checkFailures(t, fake, []string{"JSON does not match at [$.items[*].id]:\n" +
+		"[$.items[1].id] - value mismatch - expected [1] but received [2]"})
+}
+
+func checkFailures(t *testing.T, fake *fakeT, expected []string) {
+	t.Helper()
+
+	if !fake.helper {
+		t.Error("the assertion must be marked as a test helper")
+	}
+	if len(fake.failures) != len(expected) {
+		t.Fatalf("expected %d failure(s) but found %d: %q", len(expected), len(fake.failures), fake.failures)
+	}
+	for i := range expected {
+		if fake.failures[i] != expected[i] {
+			t.Errorf("wrong failure message:\n%s\nexpected:\n%s", fake.failures[i], expected[i])
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package assertjson
+
+import (
+	"github.com/go-clarum/clarum-json/report"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSuiteName is the suite of the results collected from assertions whose [TestingT] has no name.
+const DefaultSuiteName = "assertjson"
+
+var collector atomic.Pointer[report.Collector]
+
+// CollectResults adds the result of every following assertion to the collector, so that they can be written into
+// a CI report like [report.WriteJUnit]. The results are grouped in one suite per top-level test and named after
+// the test. A nil collector stops the collection.
+//
+//	func TestMain(m *testing.M) {
+//		collector := report.NewCollector()
+//		assertjson.CollectResults(collector)
+//		code := m.Run()
+//
+//		file, _ := os.Create("assertjson-report.xml")
+//		_ = report.WriteJUnit(file, "customers", collector.Suites()...)
+//		_ = file.Close()
+//		os.Exit(code)
+//	}
+func CollectResults(resultCollector *report.Collector) {
+	collector.Store(resultCollector)
+}
+
+func collect(t TestingT, title string, duration time.Duration, recorderLog string, err error) {
+	resultCollector := collector.Load()
+	if resultCollector == nil {
+		return
+	}
+
+	suiteName, name := DefaultSuiteName, title
+	if namedT, ok := t.(interface{ Name() string }); ok {
+		name = namedT.Name()
+		suiteName, _, _ = strings.Cut(name, "/")
+	}
+
+	resultCollector.Add(suiteName, report.Result{Name: name, Err: err, Log: recorderLog, Duration: duration})
+}
@@ -0,0 +1,54 @@
+package assertjson
+
+import (
+	"github.com/go-clarum/clarum-json/report"
+	"testing"
+)
+
+type unnamedT struct {
+	failures int
+}
+
+func (t *unnamedT) Helper() {
+}
+
+func (t *unnamedT) Errorf(format string, args ...any) {
+	t.failures++
+}
+
+func TestCollectResults(t *testing.T) {
+	collector := report.NewCollector()
+	CollectResults(collector)
+	defer CollectResults(nil)
+
+	Equal(&fakeT{name: "TestCustomers/active"}, "{\"id\": 1}", "{\"id\": 1}")
+	Contains(&fakeT{name: "TestCustomers/inactive"}, "{\"id\": 1}", "{\"id\": 2}")
+	Matches(&fakeT{name: "TestOrders"}, "{\"items\": []}", "$.items.length()", 0)
+	Equal(&unnamedT{}, "{\"id\": 1}", "{\"id\": ")
+
+	suites := collector.Suites()
+	if len(suites) != 3 {
+		t.Fatalf("expected 3 suites but found %+v", suites)
+	}
+
+	customers := suites[0]
+	if customers.Name != "TestCustomers" || len(customers.Results) != 2 ||
+		customers.Results[0].Name != "TestCustomers/active" || !customers.Results[0].Passed() ||
+		customers.Results[1].Name != "TestCustomers/inactive" || !customers.Results[1].Failed() ||
+		customers.Results[1].Log != "{\n  \"id\": 2, <-- value mismatch - expected [1]\n}\n" {
+		t.Errorf("wrong suite: %+v", customers)
+	}
+	if orders := suites[1]; orders.Name != "TestOrders" || len(orders.Results) != 1 || !orders.Results[0].Passed() {
+		t.Errorf("wrong suite: %+v", orders)
+	}
+	if unnamed := suites[2]; unnamed.Name != DefaultSuiteName || unnamed.Results[0].Name != "JSON does not match" ||
+		unnamed.Results[0].Passed() {
+		t.Errorf("wrong suite: %+v", unnamed)
+	}
+
+	CollectResults(nil)
+	Equal(&fakeT{name: "TestIgnored"}, "{}", "{}")
+	if len(collector.Suites()) != 3 {
+		t.Error("the results must not be collected anymore")
+	}
+}
@@ -0,0 +1,154 @@
+package assertjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-json/internal/matcher"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// UpdateSnapshotsEnv is the environment variable that switches [MatchSnapshot] to the update mode,
+// for example 'UPDATE_SNAPSHOTS=true go test ./...'.
+const UpdateSnapshotsEnv = "UPDATE_SNAPSHOTS"
+
+// SnapshotDir is the directory, relative to the package of the test, that contains the snapshots.
+const SnapshotDir = "testdata"
+
+// SnapshotT is the part of [testing.TB] used by [MatchSnapshot].
+type SnapshotT interface {
+	TestingT
+	Name() string
+}
+
+// MatchSnapshot checks that the actual JSON matches the snapshot of the test, the file 'testdata/<TestName>.json'.
+// The snapshot is an expected JSON, so it can contain markers like '@ignore@' for volatile values.
+// Subtests use subdirectories, like 'testdata/TestCustomers/active.json'. Characters that are not allowed in file names
+// on every platform, like '<' or ':', are removed from the test name.
+//
+// If the [UpdateSnapshotsEnv] environment variable is set to true, the snapshot is written from the actual JSON
+// instead, and the test does not fail. The markers of an existing snapshot are kept at their paths, so that only
+// the values that are validated are updated.
+func MatchSnapshot[A Document](t SnapshotT, actual A, options ...Option) bool {
+	t.Helper()
+
+	path := snapshotPath(t.Name())
+	snapshot, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("unable to read snapshot [%s] - %s", path, err)
+		return false
+	}
+
+	if update, _ := strconv.ParseBool(os.Getenv(UpdateSnapshotsEnv)); update {
+		if err := updateSnapshot(path, snapshot, []byte(actual)); err != nil {
+			t.Errorf("unable to update snapshot [%s] - %s", path, err)
+			return false
+		}
+		return true
+	}
+
+	if snapshot == nil {
+		t.Errorf("snapshot [%s] does not exist - run the test with %s=true to create it", path, UpdateSnapshotsEnv)
+		return false
+	}
+
+	start := time.Now()
+	recorderLog, err := build(options).Compare(snapshot, []byte(actual))
+	return check(t, fmt.Sprintf("JSON does not match snapshot [%s]", path), start, recorderLog, err)
+}
+
+// snapshotPath removes the characters of the test name that are not allowed in file names on every platform,
+// like testing.TempDir does. The slashes of subtests are kept as directories.
+func snapshotPath(testName string) string {
+	fileName := strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf {
+			const allowed = "/!#$%&()+,-.=@^_{}~ "
+			if '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || strings.ContainsRune(allowed, r) {
+				return r
+			}
+		} else if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return r
+		}
+		return -1
+	}, testName)
+
+	return filepath.Join(SnapshotDir, filepath.FromSlash(fileName)+".json")
+}
+
+// updateSnapshot writes the actual JSON to the snapshot, keeping the markers of the previous snapshot.
+// A previous snapshot that is not valid JSON is replaced entirely.
+func updateSnapshot(path string, previous []byte, actual []byte) error {
+	actualJsonObject, err := decodeNumbers(actual)
+	if err != nil {
+		return fmt.Errorf("invalid actual JSON - %s", err)
+	}
+
+	if previousJsonObject, err := decodeNumbers(previous); err == nil {
+		actualJsonObject = keepMarkers(previousJsonObject, actualJsonObject)
+	}
+
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(actualJsonObject); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content.Bytes(), 0o644)
+}
+
+// decodeNumbers decodes the JSON with the numbers as json.Number, so that they are written back unchanged.
+func decodeNumbers(value []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(value))
+	decoder.UseNumber()
+
+	var jsonObject any
+	if err := decoder.Decode(&jsonObject); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return jsonObject, nil
+}
+
+// keepMarkers returns the actual value, where the values at the paths of markers in the previous value are
+// replaced by these markers.
+func keepMarkers(previous any, actual any) any {
+	if matcher.IsIgnore(previous) {
+		return previous
+	}
+
+	switch previousValue := previous.(type) {
+	case map[string]any:
+		if actualValue, isMap := actual.(map[string]any); isMap {
+			for key, value := range actualValue {
+				if previousFieldValue, exists := previousValue[key]; exists {
+					actualValue[key] = keepMarkers(previousFieldValue, value)
+				}
+			}
+		}
+	case []any:
+		if actualValue, isSlice := actual.([]any); isSlice {
+			for i := range actualValue {
+				if i < len(previousValue) {
+					actualValue[i] = keepMarkers(previousValue[i], actualValue[i])
+				}
+			}
+		}
+	}
+	return actual
+}
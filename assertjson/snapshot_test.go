@@ -0,0 +1,138 @@
+package assertjson
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchSnapshot(t *testing.T) {
+	inTempDir(t)
+	writeFile(t, "testdata/TestCustomer.json", "{\"name\": \"Bruce\", \"id\": \"@ignore@\"}")
+
+	fake := &fakeT{name: "TestCustomer"}
+	if !MatchSnapshot(fake, "{\"id\": 42, \"name\": \"Bruce\"}") {
+		t.Error("the JSON must match the snapshot")
+	}
+	checkFailures(t, fake, nil)
+
+	MatchSnapshot(fake, "{\"id\": 42, \"name\": \"Dick\"}", Recorder(nil))
+	checkFailures(t, fake, []string{"JSON does not match snapshot [" + filepath.Join("testdata", "TestCustomer.json") + "]:\n" +
+		"[$.name] - value mismatch - expected [Bruce] but received [Dick]"})
+}
+
+func TestMatchSnapshotMissing(t *testing.T) {
+	inTempDir(t)
+
+	fake := &fakeT{name: "TestMissing"}
+	MatchSnapshot(fake, "{}")
+
+	if len(fake.failures) != 1 || !strings.Contains(fake.failures[0], "does not exist - run the test with UPDATE_SNAPSHOTS=true") {
+		t.Errorf("expected a missing snapshot failure but found %q", fake.failures)
+	}
+}
+
+func TestMatchSnapshotCreate(t *testing.T) {
+	inTempDir(t)
+	t.Setenv(UpdateSnapshotsEnv, "true")
+
+	fake := &fakeT{name: "TestCustomers/active_<1>:a|b"}
+	if !MatchSnapshot(fake, "{\"name\": \"Bruce\", \"balance\": 10000000000000000000001, \"tags\": [\"<a&b>\"]}") {
+		t.Error("the update mode must not fail")
+	}
+	checkFailures(t, fake, nil)
+
+	// the characters that are not allowed in file names are removed
+	checkFile(t, "testdata/TestCustomers/active_1ab.json", "{\n"+
+		"  \"balance\": 10000000000000000000001,\n"+
+		"  \"name\": \"Bruce\",\n"+
+		"  \"tags\": [\n"+
+		"    \"<a&b>\"\n"+
+		"  ]\n"+
+		"}\n")
+}
+
+func TestMatchSnapshotUpdateKeepsMarkers(t *testing.T) {
+	inTempDir(t)
+	t.Setenv(UpdateSnapshotsEnv, "1")
+	writeFile(t, "testdata/TestOrder.json", "{\"id\": \"@ignore@\", \"status\": \"NEW\", "+
+		"\"items\": [{\"sku\": \"A\", \"created\": \"@ignore@\"}], \"meta\": \"@ignore@\", \"removed\": \"@ignore@\"}")
+
+	fake := &fakeT{name: "TestOrder"}
+	MatchSnapshot(fake, "{\"id\": 7, \"status\": \"SHIPPED\", \"meta\": {\"trace\": \"x\"}, \"items\": "+
+		"[{\"sku\": \"B\", \"created\": \"2024-01-03\"}, {\"sku\": \"C\", \"created\": \"2024-01-04\"}]}")
+	checkFailures(t, fake, nil)
+
+	checkFile(t, "testdata/TestOrder.json", "{\n"+
+		"  \"id\": \"@ignore@\",\n"+
+		"  \"items\": [\n"+
+		"    {\n"+
+		"      \"created\": \"@ignore@\",\n"+
+		"      \"sku\": \"B\"\n"+
+		"    },\n"+
+		"    {\n"+
+		"      \"created\": \"2024-01-04\",\n"+
+		"      \"sku\": \"C\"\n"+
+		"    }\n"+
+		"  ],\n"+
+		"  \"meta\": \"@ignore@\",\n"+
+		"  \"status\": \"SHIPPED\"\n"+
+		"}\n")
+
+	// the updated snapshot matches the actual JSON
+	t.Setenv(UpdateSnapshotsEnv, "")
+	MatchSnapshot(fake, "{\"id\": 8, \"status\": \"SHIPPED\", \"meta\": null, \"items\": "+
+		"[{\"sku\": \"B\", \"created\": \"2024-02-01\"}, {\"sku\": \"C\", \"created\": \"2024-01-04\"}]}")
+	checkFailures(t, fake, nil)
+}
+
+func TestMatchSnapshotUpdateInvalidJson(t *testing.T) {
+	inTempDir(t)
+	t.Setenv(UpdateSnapshotsEnv, "true")
+
+	for _, invalidJson := range []string{"{\"name\": ", "{\"a\": 1} garbage"} {
+		fake := &fakeT{name: "TestInvalid"}
+		MatchSnapshot(fake, invalidJson)
+
+		if len(fake.failures) != 1 || !strings.Contains(fake.failures[0], "invalid actual JSON") {
+			t.Errorf("expected an invalid JSON failure but found %q", fake.failures)
+		}
+		if _, err := os.Stat("testdata/TestInvalid.json"); err == nil {
+			t.Errorf("an invalid JSON must not be written: %s", invalidJson)
+		}
+	}
+}
+
+// inTempDir runs the test in an empty directory, so that the snapshots are written there.
+func inTempDir(t *testing.T) {
+	workingDir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(workingDir)
+	})
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkFile(t *testing.T, path string, expected string) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != expected {
+		t.Errorf("wrong content of [%s]:\n%s", path, content)
+	}
+}
@@ -0,0 +1,57 @@
+package batch
+
+import (
+	"github.com/go-clarum/clarum-json/comparator"
+	"runtime"
+)
+
+type Builder struct {
+	options
+}
+
+// NewDirComparator is the builder initiator. Always use the builder to create a [DirComparator]
+// as this will set the default options.
+func NewDirComparator() *Builder {
+	return &Builder{
+		options{
+			compare: func(expected []byte, actual []byte) (string, error) {
+				return comparator.NewComparator().Build().Compare(expected, actual)
+			},
+			concurrency: runtime.NumCPU(),
+			extension:   ".json",
+		},
+	}
+}
+
+// Compare sets the function that compares the content of an expected file with the one of the actual file.
+// It is called concurrently, so it must not share a recorder between calls; building a new [comparator.Comparator]
+// for every call is cheap:
+//
+//	func(expected []byte, actual []byte) (string, error) {
+//		return comparator.NewComparator().Recorder(recorder.NewDefaultRecorder()).Build().Compare(expected, actual)
+//	}
+//
+// Default is a comparator with the default options.
+func (builder *Builder) Compare(compare func(expected []byte, actual []byte) (string, error)) *Builder {
+	builder.compare = compare
+	return builder
+}
+
+// Concurrency is the number of files compared at the same time. Values lower than 1 are replaced by 1.
+// Default is the number of CPUs.
+func (builder *Builder) Concurrency(concurrency int) *Builder {
+	builder.concurrency = max(concurrency, 1)
+	return builder
+}
+
+// Extension selects the files that are compared, other files are ignored.
+// An empty extension selects all files.
+// Default is '.json'.
+func (builder *Builder) Extension(extension string) *Builder {
+	builder.extension = extension
+	return builder
+}
+
+func (builder *Builder) Build() *DirComparator {
+	return &DirComparator{options: builder.options}
+}
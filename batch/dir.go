@@ -0,0 +1,231 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-clarum/clarum-json/comparator"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the outcome of the comparison of one file.
+type Status string
+
+const (
+	// Match means that the actual file matches the expected one.
+	Match Status = "match"
+	// Mismatch means that the comparator found mismatches between the files.
+	Mismatch Status = "mismatch"
+	// MissingActual means that the expected file has no actual file at the same relative path.
+	MissingActual Status = "missing actual"
+	// UnexpectedActual means that the actual file has no expected file at the same relative path.
+	UnexpectedActual Status = "unexpected actual"
+	// Failed means that the files could not be compared, for example because one of them is not valid JSON.
+	Failed Status = "failed"
+)
+
+// DirComparator compares two directory trees of JSON files. The files are paired by their path relative
+// to the root directories and every pair is compared concurrently.
+//
+// A DirComparator is immutable and goroutine safe.
+type DirComparator struct {
+	options
+}
+
+type options struct {
+	compare     func(expected []byte, actual []byte) (string, error)
+	concurrency int
+	extension   string
+}
+
+// FileResult is the result of the comparison of one file. Err contains the error returned by the comparison
+// or the one that prevented it, and Log the recorder output.
+type FileResult struct {
+	Path     string
+	Status   Status
+	Err      error
+	Log      string
+	Duration time.Duration
+}
+
+// Summary is the result of the comparison of two directories. The results are sorted by path.
+type Summary struct {
+	Results          []FileResult
+	Matching         int
+	Mismatching      int
+	MissingActual    int
+	UnexpectedActual int
+	Failed           int
+	Duration         time.Duration
+}
+
+// Success checks if all expected files have a matching actual file and there are no other actual files.
+func (summary *Summary) Success() bool {
+	return summary.Matching == len(summary.Results)
+}
+
+func (summary *Summary) String() string {
+	return fmt.Sprintf("%d file(s) compared in [%s] - %d matching, %d mismatching, %d missing actual, "+
+		"%d unexpected actual, %d failed", len(summary.Results), summary.Duration.Round(time.Millisecond),
+		summary.Matching, summary.Mismatching, summary.MissingActual, summary.UnexpectedActual, summary.Failed)
+}
+
+// Compare compares the files of the expected directory with the files at the same relative paths in the
+// actual directory. Files that only exist in one of the directories are reported as orphans.
+//
+// The error is only returned if a directory cannot be listed or if the context is done, in which case the
+// summary is nil. The results of the single files, including their errors, are contained in the summary.
+func (dirComparator *DirComparator) Compare(ctx context.Context, expectedDir string, actualDir string) (*Summary, error) {
+	start := time.Now()
+
+	expectedFiles, err := dirComparator.listFiles(expectedDir)
+	if err != nil {
+		return nil, err
+	}
+	actualFiles, err := dirComparator.listFiles(actualDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(expectedFiles)+len(actualFiles))
+	for path := range expectedFiles {
+		paths = append(paths, path)
+	}
+	for path := range actualFiles {
+		if !expectedFiles[path] {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	results := make([]FileResult, len(paths))
+	jobs := make(chan int)
+	var workers sync.WaitGroup
+	for i := 0; i < min(dirComparator.concurrency, len(paths)); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for index := range jobs {
+				// the dispatcher may still hand out a job when the context is done
+				if ctx.Err() != nil {
+					continue
+				}
+				path := paths[index]
+				results[index] = dirComparator.compareFile(expectedDir, actualDir, path,
+					expectedFiles[path], actualFiles[path])
+			}
+		}()
+	}
+
+dispatch:
+	for index := range paths {
+		select {
+		case jobs <- index:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	workers.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	summary := &Summary{Results: results, Duration: time.Since(start)}
+	for _, result := range results {
+		switch result.Status {
+		case Match:
+			summary.Matching++
+		case Mismatch:
+			summary.Mismatching++
+		case MissingActual:
+			summary.MissingActual++
+		case UnexpectedActual:
+			summary.UnexpectedActual++
+		case Failed:
+			summary.Failed++
+		}
+	}
+	return summary, nil
+}
+
+// listFiles returns the paths of the selected files, relative to the directory and with forward slashes.
+// Symbolic links to files are followed, but symbolic links to directories are not. A broken link is selected
+// as well, so that it is reported as failed instead of being skipped without notice.
+func (dirComparator *DirComparator) listFiles(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !strings.HasSuffix(entry.Name(), dirComparator.extension) || !isFile(path, entry) {
+			return nil
+		}
+
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(relativePath)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the files of [%s] - %w", dir, err)
+	}
+	return files, nil
+}
+
+func isFile(path string, entry fs.DirEntry) bool {
+	if entry.Type()&fs.ModeSymlink == 0 {
+		return entry.Type().IsRegular()
+	}
+
+	info, err := os.Stat(path)
+	return err != nil || info.Mode().IsRegular()
+}
+
+func (dirComparator *DirComparator) compareFile(expectedDir string, actualDir string, path string,
+	expectedExists bool, actualExists bool) FileResult {
+	start := time.Now()
+	result := FileResult{Path: path}
+
+	switch {
+	case !actualExists:
+		result.Status = MissingActual
+	case !expectedExists:
+		result.Status = UnexpectedActual
+	default:
+		result.Status, result.Log, result.Err = dirComparator.compareContents(
+			filepath.Join(expectedDir, filepath.FromSlash(path)), filepath.Join(actualDir, filepath.FromSlash(path)))
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (dirComparator *DirComparator) compareContents(expectedPath string, actualPath string) (Status, string, error) {
+	expected, err := os.ReadFile(expectedPath)
+	if err != nil {
+		return Failed, "", err
+	}
+	actual, err := os.ReadFile(actualPath)
+	if err != nil {
+		return Failed, "", err
+	}
+
+	recorderLog, err := dirComparator.compare(expected, actual)
+	if err == nil {
+		return Match, recorderLog, nil
+	}
+	// errors without mismatches, like invalid JSON or exceeded limits, prevented the comparison
+	if len(comparator.Mismatches(err)) == 0 {
+		return Failed, recorderLog, err
+	}
+	return Mismatch, recorderLog, err
+}
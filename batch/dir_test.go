@@ -0,0 +1,176 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/recorder"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	expectedDir, actualDir := t.TempDir(), t.TempDir()
+	writeFiles(t, expectedDir, map[string]string{
+		"customers/1.json": "{\"name\": \"Bruce\"}",
+		"customers/2.json": "{\"name\": \"Dick\"}",
+		"orders/1.json":    "{\"id\": \"@ignore@\"}",
+		"orders/2.json":    "{\"id\": 2}",
+		"invalid.json":     "{\"id\": ",
+		"notes.txt":        "not compared",
+	})
+	writeFiles(t, actualDir, map[string]string{
+		"customers/1.json": "{\"name\": \"Bruce\"}",
+		"customers/2.json": "{\"name\": \"Jason\"}",
+		"orders/1.json":    "{\"id\": 42}",
+		"orders/3.json":    "{\"id\": 3}",
+		"invalid.json":     "{}",
+		"notes.txt":        "not compared either",
+	})
+
+	summary, err := NewDirComparator().Build().Compare(context.Background(), expectedDir, actualDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkResults(t, summary, []string{
+		"customers/1.json - match",
+		"customers/2.json - mismatch - [$.name] - value mismatch - expected [Dick] but received [Jason]",
+		"invalid.json - failed - unable to parse JSON - error [unexpected end of JSON input] - from string [{\"id\": ]",
+		"orders/1.json - match",
+		"orders/2.json - missing actual",
+		"orders/3.json - unexpected actual",
+	})
+
+	if summary.Success() || summary.Matching != 2 || summary.Mismatching != 1 || summary.MissingActual != 1 ||
+		summary.UnexpectedActual != 1 || summary.Failed != 1 {
+		t.Errorf("wrong summary: %s", summary)
+	}
+	if !strings.HasPrefix(summary.String(), "6 file(s) compared in [") ||
+		!strings.HasSuffix(summary.String(), "2 matching, 1 mismatching, 1 missing actual, 1 unexpected actual, 1 failed") {
+		t.Errorf("wrong summary: %s", summary)
+	}
+}
+
+func TestCompareOptions(t *testing.T) {
+	expectedDir, actualDir := t.TempDir(), t.TempDir()
+	files := make(map[string]string)
+	for i := 0; i < 50; i++ {
+		files[fmt.Sprintf("%02d.txt", i)] = fmt.Sprintf("{\"id\": %d}", i)
+	}
+	writeFiles(t, expectedDir, files)
+	writeFiles(t, actualDir, files)
+
+	var calls atomic.Int32
+	summary, err := NewDirComparator().
+		Extension(".txt").
+		Concurrency(4).
+		Compare(func(expected []byte, actual []byte) (string, error) {
+			calls.Add(1)
+			return comparator.NewComparator().Recorder(recorder.NewDefaultRecorder()).Build().Compare(expected, actual)
+		}).
+		Build().
+		Compare(context.Background(), expectedDir, actualDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !summary.Success() || len(summary.Results) != 50 || calls.Load() != 50 {
+		t.Errorf("expected 50 matching files: %s", summary)
+	}
+	if summary.Results[7].Path != "07.txt" || summary.Results[7].Log != "{\n  \"id\": 7,\n}\n" {
+		t.Errorf("wrong result: %+v", summary.Results[7])
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	dirComparator := NewDirComparator().Build()
+
+	_, err := dirComparator.Compare(context.Background(), filepath.Join(t.TempDir(), "missing"), t.TempDir())
+	if err == nil || !strings.HasPrefix(err.Error(), "unable to list the files of [") {
+		t.Errorf("expected a listing error but found %v", err)
+	}
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"1.json": "{}"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	summary, err := dirComparator.Compare(ctx, dir, dir)
+	if !errors.Is(err, context.Canceled) || summary != nil {
+		t.Errorf("expected the comparison to be cancelled but found %v", err)
+	}
+}
+
+func TestCompareCancelledDuringComparison(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"1.json": "{}", "2.json": "{}", "3.json": "{}"})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls atomic.Int32
+	summary, err := NewDirComparator().
+		Concurrency(1).
+		Compare(func(expected []byte, actual []byte) (string, error) {
+			calls.Add(1)
+			cancel()
+			return "", nil
+		}).
+		Build().
+		Compare(ctx, dir, dir)
+
+	if !errors.Is(err, context.Canceled) || summary != nil || calls.Load() != 1 {
+		t.Errorf("expected the comparison to stop after the first file but found %d call(s): %v", calls.Load(), err)
+	}
+}
+
+func TestCompareSymlinks(t *testing.T) {
+	expectedDir, actualDir, targetDir := t.TempDir(), t.TempDir(), t.TempDir()
+	writeFiles(t, targetDir, map[string]string{"customer.json": "{\"name\": \"Bruce\"}"})
+	writeFiles(t, expectedDir, map[string]string{"customer.json": "{\"name\": \"Bruce\"}", "broken.json": "{}"})
+	if err := os.Symlink(filepath.Join(targetDir, "customer.json"), filepath.Join(actualDir, "customer.json")); err != nil {
+		t.Skipf("symbolic links are not supported: %s", err)
+	}
+	if err := os.Symlink(filepath.Join(targetDir, "missing.json"), filepath.Join(actualDir, "broken.json")); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := NewDirComparator().Build().Compare(context.Background(), expectedDir, actualDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summary.Results) != 2 || summary.Results[0].Status != Failed || summary.Results[1].Status != Match {
+		t.Errorf("expected a failed broken link and a matching link: %+v", summary.Results)
+	}
+}
+
+func checkResults(t *testing.T, summary *Summary, expected []string) {
+	var actual []string
+	for _, result := range summary.Results {
+		line := fmt.Sprintf("%s - %s", result.Path, result.Status)
+		if result.Err != nil {
+			line += " - " + result.Err.Error()
+		}
+		actual = append(actual, line)
+	}
+
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong results:\n%s", strings.Join(actual, "\n"))
+	}
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for path, content := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/internal"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"github.com/go-clarum/clarum-json/recorder"
+	"github.com/go-clarum/clarum-json/report"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const stdinPath = "-"
+
+// Recorder names accepted by the --recorder flag.
+const (
+	recorderDefault    = "default"
+	recorderSideBySide = "side-by-side"
+	recorderDiff       = "diff"
+	recorderColor      = "color"
+	recorderJson       = "json"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorRed   = "\033[31m"
+	colorGreen = "\033[32m"
+	colorCyan  = "\033[36m"
+)
+
+func runCompare(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: clarum-json compare [flags] <expected> [actual]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Reads a document from the standard input if its path is '-' or the actual path is missing.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	options := addCompareFlags(flags)
+
+	paths, err := parseInterspersed(flags, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitMatch
+	} else if err != nil {
+		return exitError
+	}
+	if len(paths) == 0 || len(paths) > 2 {
+		flags.Usage()
+		return exitError
+	}
+	if len(paths) == 1 {
+		paths = append(paths, stdinPath)
+	}
+	if paths[0] == stdinPath && paths[1] == stdinPath {
+		fmt.Fprintln(stderr, "only one document can be read from the standard input")
+		return exitError
+	}
+
+	config, err := options.config()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	expected, expectedContent, err := readJson("expected", paths[0], stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	actual, actualContent, err := readJson("actual", paths[1], stdin)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	start := time.Now()
+	recorderLog, compareErr := config.compare(expected, actual)
+	result := report.Result{Name: paths[1], Err: compareErr, Log: recorderLog, Duration: time.Since(start),
+		Expected: expectedContent, Actual: actualContent}
+	if err := config.writeReports(report.Suite{Name: paths[0], Results: []report.Result{result}}); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if config.recorderName == recorderJson {
+		err = writeJsonReport(stdout, compareErr)
+	} else {
+		err = config.writeReport(stdout, recorderLog, compareErr)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if compareErr != nil {
+		return exitMismatch
+	}
+	return exitMatch
+}
+
+// compareFlags are the flags that configure the comparator, shared by the commands.
+type compareFlags struct {
+	strict       *bool
+	ignore       stringList
+	recorderName *string
+	failFast     *bool
+	maxErrors    *int
+	junit        *string
+	html         *string
+	markdown     *string
+}
+
+func addCompareFlags(flags *flag.FlagSet) *compareFlags {
+	options := &compareFlags{
+		strict: flags.Bool("strict", true, "fail on fields of the actual JSON that are not expected"),
+		recorderName: flags.String("recorder", recorderDefault,
+			"output format: default, side-by-side, diff, color or json"),
+		failFast:  flags.Bool("fail-fast", false, "stop at the first mismatch"),
+		maxErrors: flags.Int("max-errors", 0, "maximum number of mismatches reported, 0 means no limit"),
+		junit:     flags.String("junit", "", "write a JUnit XML report to the file"),
+		html:      flags.String("html", "", "write an HTML report to the file"),
+		markdown:  flags.String("markdown", "", "write a Markdown report to the file, for example for a pull request comment"),
+	}
+	flags.Var(&options.ignore, "ignore", "JSONPath of values to ignore, can be repeated")
+	return options
+}
+
+// config validates the flags once they are parsed.
+func (options *compareFlags) config() (*compareConfig, error) {
+	if _, err := newRecorder(*options.recorderName); err != nil {
+		return nil, err
+	}
+
+	config := &compareConfig{
+		strict:       *options.strict,
+		recorderName: *options.recorderName,
+		failFast:     *options.failFast,
+		maxErrors:    *options.maxErrors,
+		junit:        *options.junit,
+		html:         *options.html,
+		markdown:     *options.markdown,
+	}
+	for _, expression := range options.ignore {
+		if _, err := jsonpath.Parse(expression); err != nil {
+			return nil, err
+		}
+		config.ignore = append(config.ignore, expression)
+	}
+	return config, nil
+}
+
+// compareConfig compares documents with the options given on the command line.
+// It is goroutine safe, as every comparison uses a new recorder.
+type compareConfig struct {
+	strict       bool
+	ignore       []string
+	recorderName string
+	failFast     bool
+	maxErrors    int
+	junit        string
+	html         string
+	markdown     string
+}
+
+func (config *compareConfig) compare(expected any, actual any) (string, error) {
+	jsonRecorder, _ := newRecorder(config.recorderName)
+	builder := comparator.NewComparator().
+		StrictObjectCheck(config.strict).
+		PathsToIgnore(config.ignore...).
+		MaxErrors(config.maxErrors).
+		Recorder(jsonRecorder)
+	if config.failFast {
+		builder.FailFast()
+	}
+
+	return builder.Build().CompareValues(expected, actual)
+}
+
+// compareBytes parses the documents before comparing them.
+func (config *compareConfig) compareBytes(expected []byte, actual []byte) (string, error) {
+	expectedJsonObject, err := parseJson("expected", expected)
+	if err != nil {
+		return "", err
+	}
+	actualJsonObject, err := parseJson("actual", actual)
+	if err != nil {
+		return "", err
+	}
+
+	return config.compare(expectedJsonObject, actualJsonObject)
+}
+
+// writeReports writes the report files requested on the command line.
+func (config *compareConfig) writeReports(suite report.Suite) error {
+	if err := writeReportFile(config.junit, "JUnit", func(output io.Writer) error {
+		return report.WriteJUnit(output, "clarum-json", suite)
+	}); err != nil {
+		return err
+	}
+	if err := writeReportFile(config.html, "HTML", func(output io.Writer) error {
+		return report.WriteHTML(output, "clarum-json", suite)
+	}); err != nil {
+		return err
+	}
+	return writeReportFile(config.markdown, "Markdown", func(output io.Writer) error {
+		return report.WriteMarkdown(output, "clarum-json", suite)
+	})
+}
+
+// writeReportFile writes a report to the file, nothing is written if the path is empty.
+func writeReportFile(path string, format string, write func(output io.Writer) error) error {
+	if path == "" {
+		return nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to write %s report - %s", format, err)
+	}
+	defer file.Close()
+
+	if err := write(file); err != nil {
+		return fmt.Errorf("unable to write %s report - %s", format, err)
+	}
+	return file.Close()
+}
+
+func newRecorder(name string) (recorder.Recorder, error) {
+	switch name {
+	case recorderDefault:
+		return recorder.NewDefaultRecorder(), nil
+	case recorderJson:
+		return internal.NewNoopRecorder(), nil
+	case recorderSideBySide:
+		return recorder.NewSideBySideRecorder(), nil
+	case recorderDiff, recorderColor:
+		return recorder.NewUnifiedDiffRecorder(), nil
+	default:
+		return nil, fmt.Errorf("unknown recorder [%s] - expected one of default, side-by-side, diff, color or json", name)
+	}
+}
+
+// readJson returns the parsed document and its content.
+func readJson(name string, path string, stdin io.Reader) (any, []byte, error) {
+	var content []byte
+	var err error
+	if path == stdinPath {
+		content, err = io.ReadAll(stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read %s JSON - %s", name, err)
+	}
+
+	jsonObject, err := parseJson(name, content)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w - file [%s]", err, path)
+	}
+	return jsonObject, content, nil
+}
+
+func parseJson(name string, content []byte) (any, error) {
+	var jsonObject any
+	if err := json.Unmarshal(content, &jsonObject); err != nil {
+		return nil, fmt.Errorf("unable to parse %s JSON - error [%s]", name, err)
+	}
+	return jsonObject, nil
+}
+
+// writeReport writes the errors followed by the recorder output. Nothing is written if the documents match.
+func (config *compareConfig) writeReport(output io.Writer, recorderLog string, compareErr error) error {
+	if compareErr == nil {
+		return nil
+	}
+
+	if config.recorderName == recorderColor {
+		recorderLog = colorize(recorderLog)
+	}
+
+	text := compareErr.Error() + "\n"
+	if recorderLog != "" {
+		text += "\n" + recorderLog
+	}
+
+	_, err := io.WriteString(output, text)
+	return err
+}
+
+// colorize colors the lines of a unified diff for terminals.
+func colorize(unifiedDiff string) string {
+	var result strings.Builder
+	for _, line := range strings.SplitAfter(unifiedDiff, "\n") {
+		content := strings.TrimSuffix(line, "\n")
+		switch {
+		case content == "":
+			result.WriteString(line)
+			continue
+		case strings.HasPrefix(content, "---"), strings.HasPrefix(content, "+++"):
+			result.WriteString(content)
+		case strings.HasPrefix(content, "-"):
+			result.WriteString(colorRed + content + colorReset)
+		case strings.HasPrefix(content, "+"):
+			result.WriteString(colorGreen + content + colorReset)
+		case strings.HasPrefix(content, "@@"):
+			result.WriteString(colorCyan + content + colorReset)
+		default:
+			result.WriteString(content)
+		}
+		result.WriteString(line[len(content):])
+	}
+	return result.String()
+}
+
+type jsonReport struct {
+	Match      bool           `json:"match"`
+	Mismatches []jsonMismatch `json:"mismatches"`
+	Errors     []string       `json:"errors"`
+}
+
+type jsonMismatch struct {
+	Kind     comparator.MismatchKind `json:"kind"`
+	Path     string                  `json:"path"`
+	Pointer  string                  `json:"pointer"`
+	Expected any                     `json:"expected"`
+	Actual   any                     `json:"actual"`
+	Message  string                  `json:"message"`
+}
+
+// writeJsonReport writes the mismatches in a machine-readable form. Errors that are not mismatches,
+// like exceeded limits, are only listed with their message.
+func writeJsonReport(output io.Writer, compareErr error) error {
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(newJsonReport(compareErr))
+}
+
+func newJsonReport(compareErr error) jsonReport {
+	fileReport := jsonReport{Match: compareErr == nil, Mismatches: []jsonMismatch{}, Errors: []string{}}
+	result := report.Result{Err: compareErr}
+	for _, mismatch := range result.Mismatches() {
+		fileReport.Mismatches = append(fileReport.Mismatches, jsonMismatch{
+			Kind:     mismatch.Kind,
+			Path:     mismatch.Path,
+			Pointer:  mismatch.Pointer,
+			Expected: mismatch.Expected,
+			Actual:   mismatch.Actual,
+			Message:  mismatch.Error(),
+		})
+	}
+	for _, otherError := range result.Errors() {
+		fileReport.Errors = append(fileReport.Errors, otherError.Error())
+	}
+	return fileReport
+}
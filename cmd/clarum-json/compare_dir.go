@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/go-clarum/clarum-json/batch"
+	"github.com/go-clarum/clarum-json/report"
+	"io"
+	"runtime"
+	"strings"
+)
+
+func runCompareDir(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compare-dir", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: clarum-json compare-dir [flags] <expected-dir> <actual-dir>")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Compares the JSON files of both directories that have the same relative path.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+	options := addCompareFlags(flags)
+	concurrency := flags.Int("concurrency", runtime.NumCPU(), "number of files compared at the same time")
+
+	dirs, err := parseInterspersed(flags, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitMatch
+	} else if err != nil {
+		return exitError
+	}
+	if len(dirs) != 2 {
+		flags.Usage()
+		return exitError
+	}
+
+	config, err := options.config()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	summary, err := batch.NewDirComparator().
+		Compare(config.compareBytes).
+		Concurrency(*concurrency).
+		Build().
+		Compare(context.Background(), dirs[0], dirs[1])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if err := config.writeReports(report.FromSummary(dirs[0], summary)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if config.recorderName == recorderJson {
+		err = writeJsonSummary(stdout, summary)
+	} else {
+		err = config.writeSummary(stdout, summary)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+
+	if !summary.Success() {
+		return exitMismatch
+	}
+	return exitMatch
+}
+
+// writeSummary writes the files that do not match, with their errors and recorder output, followed by the counts.
+func (config *compareConfig) writeSummary(output io.Writer, summary *batch.Summary) error {
+	var text strings.Builder
+	for _, result := range summary.Results {
+		if result.Status == batch.Match {
+			continue
+		}
+
+		text.WriteString(fmt.Sprintf("%s - %s\n", result.Path, result.Status))
+		if result.Err != nil {
+			var fileReport strings.Builder
+			_ = config.writeReport(&fileReport, result.Log, result.Err)
+			text.WriteString(indent(fileReport.String()))
+		}
+		text.WriteString("\n")
+	}
+	text.WriteString(summary.String() + "\n")
+
+	_, err := io.WriteString(output, text.String())
+	return err
+}
+
+func indent(text string) string {
+	lines := strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		if line != "\n" {
+			lines[i] = "  " + line
+		}
+	}
+	return strings.Join(lines, "") + "\n"
+}
+
+type jsonSummary struct {
+	Success          bool       `json:"success"`
+	Matching         int        `json:"matching"`
+	Mismatching      int        `json:"mismatching"`
+	MissingActual    int        `json:"missingActual"`
+	UnexpectedActual int        `json:"unexpectedActual"`
+	Failed           int        `json:"failed"`
+	Files            []jsonFile `json:"files"`
+}
+
+type jsonFile struct {
+	Path   string       `json:"path"`
+	Status batch.Status `json:"status"`
+	jsonReport
+}
+
+func writeJsonSummary(output io.Writer, summary *batch.Summary) error {
+	summaryReport := jsonSummary{
+		Success:          summary.Success(),
+		Matching:         summary.Matching,
+		Mismatching:      summary.Mismatching,
+		MissingActual:    summary.MissingActual,
+		UnexpectedActual: summary.UnexpectedActual,
+		Failed:           summary.Failed,
+		Files:            []jsonFile{},
+	}
+	for _, result := range summary.Results {
+		file := jsonFile{result.Path, result.Status, newJsonReport(result.Err)}
+		file.Match = result.Status == batch.Match
+		summaryReport.Files = append(summaryReport.Files, file)
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(summaryReport)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCompareDir(t *testing.T) {
+	expectedDir, actualDir := writeDir(t, map[string]string{
+		"1.json":     "{\"id\": 1, \"meta\": {\"trace\": 1}}",
+		"2.json":     "{\"name\": \"Bruce\"}",
+		"sub/3.json": "{\"id\": 3}",
+		"4.json":     "{\"id\": ",
+	}), writeDir(t, map[string]string{
+		"1.json":     "{\"id\": 1, \"meta\": {\"trace\": 2}}",
+		"2.json":     "{\"name\": \"Dick\"}",
+		"5.json":     "{}",
+		"4.json":     "{}",
+		"sub/6.json": "{}",
+	})
+
+	exitCode, stdout, stderr := runCommand("", "compare-dir", "--ignore", "$.meta", "--concurrency=2",
+		expectedDir, actualDir)
+
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+	checkOutput(t, "2.json - mismatch\n"+
+		"  [$.name] - value mismatch - expected [Bruce] but received [Dick]\n\n"+
+		"  {\n    \"name\": Dick, <-- value mismatch - expected [Bruce]\n  }\n\n"+
+		"4.json - failed\n"+
+		"  unable to parse expected JSON - error [unexpected end of JSON input]\n\n"+
+		"5.json - unexpected actual\n\n"+
+		"sub/3.json - missing actual\n\n"+
+		"sub/6.json - unexpected actual\n\n"+
+		"6 file(s) compared in [DURATION] - 1 matching, 1 mismatching, 1 missing actual, 2 unexpected actual, 1 failed\n",
+		regexp.MustCompile(`in \[[^]]+]`).ReplaceAllString(stdout, "in [DURATION]"))
+}
+
+func TestCompareDirMatch(t *testing.T) {
+	files := map[string]string{"1.json": "{\"id\": 1}", "sub/2.json": "[1, 2]"}
+	expectedDir, actualDir := writeDir(t, files), writeDir(t, files)
+
+	exitCode, stdout, stderr := runCommand("", "compare-dir", expectedDir, actualDir)
+
+	checkExitCode(t, exitMatch, exitCode, stderr)
+	if !regexp.MustCompile(`^2 file\(s\) compared in \[.+] - 2 matching, 0 mismatching`).MatchString(stdout) {
+		t.Errorf("wrong output:\n%s", stdout)
+	}
+}
+
+func TestCompareDirJson(t *testing.T) {
+	expectedDir, actualDir := writeDir(t, map[string]string{"1.json": "{\"id\": 1}", "2.json": "{}"}),
+		writeDir(t, map[string]string{"1.json": "{\"id\": 2}"})
+
+	exitCode, stdout, stderr := runCommand("", "compare-dir", "--recorder=json", expectedDir, actualDir)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+
+	var summary jsonSummary
+	if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, stdout)
+	}
+	if summary.Success || summary.Mismatching != 1 || summary.MissingActual != 1 || len(summary.Files) != 2 {
+		t.Errorf("wrong summary: %s", stdout)
+	}
+	if file := summary.Files[0]; file.Path != "1.json" || file.Match || len(file.Mismatches) != 1 ||
+		file.Mismatches[0].Path != "$.id" {
+		t.Errorf("wrong file: %+v", file)
+	}
+	if file := summary.Files[1]; file.Path != "2.json" || file.Status != "missing actual" || file.Match {
+		t.Errorf("wrong file: %+v", file)
+	}
+}
+
+func TestCompareDirJUnit(t *testing.T) {
+	expectedDir, actualDir := writeDir(t, map[string]string{"1.json": "{\"id\": 1}", "2.json": "{}", "3.json": "{}"}),
+		writeDir(t, map[string]string{"1.json": "{\"id\": 2}", "3.json": "{}"})
+	junit := filepath.Join(t.TempDir(), "report.xml")
+
+	exitCode, _, stderr := runCommand("", "compare-dir", "--junit="+junit, expectedDir, actualDir)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+
+	content, err := os.ReadFile(junit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"tests=\"3\" failures=\"1\" errors=\"1\"",
+		"<testcase name=\"1.json\" classname=\"" + expectedDir + "\"",
+		"<error message=\"missing actual - no actual file with the same relative path\" type=\"error\">",
+		"<testcase name=\"3.json\" classname=\"" + expectedDir + "\"",
+	} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("the JUnit report does not contain [%s]:\n%s", expected, content)
+		}
+	}
+}
+
+func TestCompareDirErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	exitCode, _, stderr := runCommand("", "compare-dir", dir)
+	if exitCode != exitError || !regexp.MustCompile(`^Usage: clarum-json compare-dir`).MatchString(stderr) {
+		t.Errorf("expected the usage [%d]:\n%s", exitCode, stderr)
+	}
+
+	exitCode, _, stderr = runCommand("", "compare-dir", dir, filepath.Join(dir, "missing"))
+	if exitCode != exitError || !regexp.MustCompile(`^unable to list the files of \[`).MatchString(stderr) {
+		t.Errorf("expected a listing error [%d]:\n%s", exitCode, stderr)
+	}
+}
+
+func writeDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for path, content := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
@@ -0,0 +1,89 @@
+// Command clarum-json compares JSON documents with the same rules as the comparator of this module,
+// so that failed validations can be reproduced outside of the tests.
+//
+// Usage:
+//
+//	clarum-json compare [flags] <expected> [actual]
+//	clarum-json compare-dir [flags] <expected-dir> <actual-dir>
+//
+// The documents are read from files, or from the standard input if the path is '-' or the actual path is missing.
+// The compare-dir command compares the files of both directories that have the same relative path.
+// The exit code is 0 if the documents match, 1 if they do not match and 2 for all other errors.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	exitMatch    = 0
+	exitMismatch = 1
+	exitError    = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitError
+	}
+
+	switch args[0] {
+	case "compare":
+		return runCompare(args[1:], stdin, stdout, stderr)
+	case "compare-dir":
+		return runCompareDir(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		printUsage(stdout)
+		return exitMatch
+	default:
+		fmt.Fprintf(stderr, "unknown command [%s]\n\n", args[0])
+		printUsage(stderr)
+		return exitError
+	}
+}
+
+func printUsage(output io.Writer) {
+	fmt.Fprintln(output, "Usage: clarum-json <command> [flags] [arguments]")
+	fmt.Fprintln(output)
+	fmt.Fprintln(output, "Commands:")
+	fmt.Fprintln(output, "  compare       compare an expected JSON with an actual one")
+	fmt.Fprintln(output, "  compare-dir   compare the JSON files of two directories")
+	fmt.Fprintln(output)
+	fmt.Fprintln(output, "Run 'clarum-json <command> -h' for the flags of a command.")
+}
+
+// parseInterspersed parses the flags wherever they are placed, so they can also follow the positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		if flags.NArg() == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+}
+
+// stringList is a flag that can be repeated.
+type stringList []string
+
+func (list *stringList) String() string {
+	return strings.Join(*list, ", ")
+}
+
+func (list *stringList) Set(value string) error {
+	*list = append(*list, value)
+	return nil
+}
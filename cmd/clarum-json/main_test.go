@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareMatch(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\", \"id\": \"@ignore@\"}")
+	actual := writeJson(t, "actual.json", "{\"id\": 7, \"name\": \"Bruce\"}")
+
+	exitCode, stdout, stderr := runCommand("", "compare", expected, actual)
+
+	checkExitCode(t, exitMatch, exitCode, stderr)
+	checkOutput(t, "", stdout)
+}
+
+func TestCompareMismatch(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\"}")
+	actual := writeJson(t, "actual.json", "{\"name\": \"Dick\"}")
+
+	exitCode, stdout, stderr := runCommand("", "compare", expected, actual)
+
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+	checkOutput(t, "[$.name] - value mismatch - expected [Bruce] but received [Dick]\n\n"+
+		"{\n  \"name\": Dick, <-- value mismatch - expected [Bruce]\n}\n", stdout)
+}
+
+func TestCompareStdin(t *testing.T) {
+	expected := writeJson(t, "expected.json", "[1, 2]")
+
+	exitCode, _, stderr := runCommand("[1, 2]", "compare", expected)
+	checkExitCode(t, exitMatch, exitCode, stderr)
+
+	exitCode, _, stderr = runCommand("[1, 3]", "compare", "-", expected)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+
+	exitCode, _, stderr = runCommand("[1, 2]", "compare", "-", "-")
+	checkExitCode(t, exitError, exitCode, stderr)
+	checkOutput(t, "only one document can be read from the standard input\n", stderr)
+}
+
+func TestCompareFlags(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\", \"meta\": {\"trace\": 1}, \"old\": true}")
+	actual := writeJson(t, "actual.json", "{\"name\": \"Bruce\", \"meta\": {\"trace\": 2}, \"age\": 37}")
+
+	exitCode, stdout, stderr := runCommand("", "compare", expected, actual, "--strict=false",
+		"--ignore", "$.meta", "--ignore", "$.old")
+	checkExitCode(t, exitMatch, exitCode, stderr)
+	checkOutput(t, "", stdout)
+
+	exitCode, stdout, stderr = runCommand("", "compare", "--ignore", "$.meta", "--ignore", "$.old", expected, actual)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+	if !strings.HasPrefix(stdout, "[$] - number of fields does not match\n[$.age] - unexpected field\n\n") ||
+		!strings.Contains(stdout, "\"meta\":  <-- ignoring field\n") {
+		t.Errorf("wrong output:\n%s", stdout)
+	}
+}
+
+func TestCompareFailFast(t *testing.T) {
+	expected := writeJson(t, "expected.json", "[1, 2, 3]")
+	actual := writeJson(t, "actual.json", "[4, 5, 6]")
+
+	exitCode, stdout, stderr := runCommand("", "compare", "--fail-fast", expected, actual)
+
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+	if !strings.HasPrefix(stdout, "[$[0]] - value mismatch - expected [1] but received [4]\n"+
+		"comparison stopped after [1] mismatch(es) - [2] check(s) skipped\n") {
+		t.Errorf("wrong output:\n%s", stdout)
+	}
+}
+
+func TestCompareRecorders(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\"}")
+	actual := writeJson(t, "actual.json", "{\"name\": \"Dick\"}")
+	header := "[$.name] - value mismatch - expected [Bruce] but received [Dick]\n\n"
+
+	_, stdout, _ := runCommand("", "compare", "--recorder=side-by-side", expected, actual)
+	checkOutput(t, header+
+		"{                    {\n"+
+		"  \"name\": \"Bruce\", |   \"name\": \"Dick\",\n"+
+		"}                    }\n", stdout)
+
+	_, stdout, _ = runCommand("", "compare", "--recorder=diff", expected, actual)
+	checkOutput(t, header+
+		"--- expected\n+++ actual\n@@ -1,3 +1,3 @@\n {\n-  \"name\": \"Bruce\"\n+  \"name\": \"Dick\"\n }\n", stdout)
+
+	_, stdout, _ = runCommand("", "compare", "--recorder=color", expected, actual)
+	checkOutput(t, header+
+		"--- expected\n+++ actual\n\033[36m@@ -1,3 +1,3 @@\033[0m\n {\n"+
+		"\033[31m-  \"name\": \"Bruce\"\033[0m\n\033[32m+  \"name\": \"Dick\"\033[0m\n }\n", stdout)
+}
+
+func TestCompareJsonRecorder(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\", \"items\": [1, 2]}")
+	actual := writeJson(t, "actual.json", "{\"name\": \"Dick\", \"items\": [1, 2]}")
+
+	exitCode, stdout, stderr := runCommand("", "compare", "--recorder=json", "--max-errors=1", expected, actual)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+
+	var report jsonReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, stdout)
+	}
+	if report.Match || len(report.Mismatches) != 1 || len(report.Errors) != 0 {
+		t.Errorf("wrong report: %s", stdout)
+	}
+
+	mismatch := report.Mismatches[0]
+	if mismatch.Kind != "value mismatch" || mismatch.Path != "$.name" || mismatch.Pointer != "/name" ||
+		mismatch.Expected != "Bruce" || mismatch.Actual != "Dick" {
+		t.Errorf("wrong mismatch: %+v", mismatch)
+	}
+
+	_, stdout, _ = runCommand("", "compare", "--recorder=json", expected, expected)
+	checkOutput(t, "{\n  \"match\": true,\n  \"mismatches\": [],\n  \"errors\": []\n}\n", stdout)
+}
+
+func TestCompareJUnit(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\"}")
+	actual := writeJson(t, "actual.json", "{\"name\": \"Dick\"}")
+	junit := filepath.Join(t.TempDir(), "report.xml")
+
+	exitCode, _, stderr := runCommand("", "compare", "--junit", junit, expected, actual)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+
+	content, err := os.ReadFile(junit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "<testsuite name=\""+expected+"\" tests=\"1\" failures=\"1\" errors=\"0\"") ||
+		!strings.Contains(string(content), "<testcase name=\""+actual+"\" classname=\""+expected+"\"") ||
+		!strings.Contains(string(content), "<![CDATA[[$.name] - value mismatch - expected [Bruce] but received [Dick]") {
+		t.Errorf("wrong JUnit report:\n%s", content)
+	}
+
+	exitCode, _, stderr = runCommand("", "compare", "--junit", t.TempDir(), expected, actual)
+	if exitCode != exitError || !strings.HasPrefix(stderr, "unable to write JUnit report") {
+		t.Errorf("expected a write error [%d]:\n%s", exitCode, stderr)
+	}
+}
+
+func TestCompareHTML(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\"}")
+	actual := writeJson(t, "actual.json", "{\"name\": \"Dick\"}")
+	html := filepath.Join(t.TempDir(), "report.html")
+
+	exitCode, _, stderr := runCommand("", "compare", "--html", html, expected, actual)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+
+	content, err := os.ReadFile(html)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "<tr><td>value mismatch</td><td>1</td></tr>") ||
+		!strings.Contains(string(content), "<div class=\"leaf mismatch\"><span class=\"key\">&#34;name&#34;</span>: &#34;Dick&#34;") {
+		t.Errorf("wrong HTML report:\n%s", content)
+	}
+}
+
+func TestCompareMarkdown(t *testing.T) {
+	expected := writeJson(t, "expected.json", "{\"name\": \"Bruce\"}")
+	actual := writeJson(t, "actual.json", "{\"name\": \"Dick\"}")
+	markdown := filepath.Join(t.TempDir(), "report.md")
+
+	exitCode, _, stderr := runCommand("", "compare", "--markdown", markdown, expected, actual)
+	checkExitCode(t, exitMismatch, exitCode, stderr)
+
+	content, err := os.ReadFile(markdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "| `$.name` | value mismatch | `\"Bruce\"` | `\"Dick\"` |\n") ||
+		!strings.Contains(string(content), "```diff\n--- expected\n+++ actual\n") {
+		t.Errorf("wrong Markdown report:\n%s", content)
+	}
+}
+
+func TestCompareErrors(t *testing.T) {
+	valid := writeJson(t, "valid.json", "{}")
+	invalid := writeJson(t, "invalid.json", "{\"name\": ")
+
+	testCases := []struct {
+		args   []string
+		stderr string
+	}{
+		{[]string{}, "Usage: clarum-json <command>"},
+		{[]string{"merge"}, "unknown command [merge]"},
+		{[]string{"compare"}, "Usage: clarum-json compare"},
+		{[]string{"compare", valid, valid, valid}, "Usage: clarum-json compare"},
+		{[]string{"compare", "--strict=maybe", valid, valid}, "invalid boolean value"},
+		{[]string{"compare", "--recorder=xml", valid, valid}, "unknown recorder [xml]"},
+		{[]string{"compare", "--ignore", "$.items[", valid, valid}, "invalid JSONPath [$.items[]"},
+		{[]string{"compare", valid, filepath.Join(t.TempDir(), "missing.json")}, "unable to read actual JSON"},
+		{[]string{"compare", invalid, valid}, "unable to parse expected JSON - error [unexpected end of JSON input] - file [" + invalid + "]"},
+	}
+
+	for _, testCase := range testCases {
+		exitCode, _, stderr := runCommand("", testCase.args...)
+		if exitCode != exitError || !strings.Contains(stderr, testCase.stderr) {
+			t.Errorf("wrong result for %q - exit code [%d]:\n%s", testCase.args, exitCode, stderr)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	exitCode, stdout, _ := runCommand("", "help")
+	if exitCode != exitMatch || !strings.HasPrefix(stdout, "Usage: clarum-json") {
+		t.Errorf("wrong help output [%d]:\n%s", exitCode, stdout)
+	}
+
+	exitCode, _, stderr := runCommand("", "compare", "-h")
+	if exitCode != exitMatch || !strings.Contains(stderr, "-ignore value") {
+		t.Errorf("wrong help output [%d]:\n%s", exitCode, stderr)
+	}
+}
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	exitCode := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func writeJson(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func checkExitCode(t *testing.T, expected int, actual int, stderr string) {
+	if expected != actual {
+		t.Errorf("expected exit code [%d] but was [%d]:\n%s", expected, actual, stderr)
+	}
+}
+
+func checkOutput(t *testing.T, expected string, actual string) {
+	if expected != actual {
+		t.Errorf("wrong output:\n%q\nexpected:\n%q", actual, expected)
+	}
+}
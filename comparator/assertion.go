@@ -0,0 +1,218 @@
+package comparator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-json/internal"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"math"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+const lengthSuffix = ".length()"
+
+// Assertion validates only the values selected by a JSONPath in the actual JSON, instead of the entire document.
+//
+// The Expected value can be any value that can be marshalled to JSON, raw JSON or the '@ignore@' marker,
+// which only checks that the path selects a value. If the Path ends with '.length()', the Expected value is compared
+// to the length of the selected array, object or string. If the Path selects multiple values, each one of them
+// must match the Expected value.
+type Assertion struct {
+	Path     string
+	Expected any
+}
+
+// Assert validates the actual JSON against the assertions, in the given order.
+// It returns the same errors and recorder output as [Comparator.Compare], where the paths of the assertions
+// are used as field names.
+func (comparator *Comparator) Assert(actual []byte, assertions ...Assertion) (string, error) {
+	comparator.logger.Debug(fmt.Sprintf("json comparator - asserting %d path(s) in [%s]", len(assertions), actual))
+	if comparator.err != nil {
+		return "", comparator.err
+	}
+	if len(comparator.pathsToIgnore) > 0 {
+		return "", errIgnoreNotSupported
+	}
+
+	actualJsonObject, err := unmarshalJson(actual)
+	if err != nil {
+		return "", err
+	}
+
+	comparison := comparator.begin(context.Background())
+	var compareErrors []error
+	for _, assertion := range assertions {
+		if comparison.stopped(compareErrors) {
+			comparison.skip(assertion.Expected)
+			continue
+		}
+
+		compareErrors, err = comparison.assert(assertion, actualJsonObject, compareErrors)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(compareErrors) > 0 {
+		comparator.logger.Debug("json comparator - assertions failed")
+	} else {
+		comparator.logger.Debug("json comparator - assertions passed")
+	}
+
+	err = comparison.result(compareErrors)
+	return comparator.recorder.GetLog(), err
+}
+
+// CompareAt compares the expected JSON only with the part of the actual JSON selected by the path,
+// for example the payload inside of an envelope. The errors contain the paths of the full actual JSON.
+// If the path selects multiple values, each of them is compared with the expected JSON.
+func (comparator *Comparator) CompareAt(expected []byte, actual []byte, path string) (string, error) {
+	comparator.logger.Debug(fmt.Sprintf("json comparator - comparing [%s] to [%s] at [%s]", expected, actual, path))
+	if comparator.err != nil {
+		return "", comparator.err
+	}
+
+	expectedJsonObject, err := unmarshalJson(expected)
+	if err != nil {
+		return "", err
+	}
+
+	return comparator.Assert(actual, Assertion{path, expectedJsonObject})
+}
+
+func (comparator *Comparator) assert(assertion Assertion, actual any, compareErrors []error) ([]error, error) {
+	expression, checkLength := strings.CutSuffix(assertion.Path, lengthSuffix)
+
+	path, err := jsonpath.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := toJsonValue(assertion.Expected)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := path.Select(actual)
+	if len(nodes) == 0 {
+		pointer, _ := path.Pointer()
+		return handleMissingField(assertion.Path, pointer, assertion.Path, expected, "",
+			comparator.recorder, compareErrors), nil
+	}
+
+	for _, node := range nodes {
+		if checkLength {
+			compareErrors = comparator.assertLength(node, expected, compareErrors)
+		} else {
+			compareErrors = comparator.compareRoot(node.Path, node.Pointer, node.Path,
+				expected, node.Value, compareErrors)
+		}
+	}
+
+	return compareErrors, nil
+}
+
+func (comparator *Comparator) assertLength(node jsonpath.Node, expected any, compareErrors []error) []error {
+	lengthPath := node.Path + lengthSuffix
+
+	length, hasLength := lengthOf(node.Value)
+	if !hasLength {
+		baseErrorMessage := fmt.Sprintf("value has no length - found [%s]",
+			convertToJsonType(node.Value))
+
+		comparator.recorder.AppendFieldName("", lengthPath).AppendValidationErrorSignal(baseErrorMessage)
+		internal.RecordPair(comparator.recorder, lengthPath, lengthPath, expected, node.Value, baseErrorMessage)
+		return append(compareErrors, &Mismatch{TypeMismatch, lengthPath, node.Pointer, expected, node.Value,
+			fmt.Sprintf("[%s] - %s", lengthPath, baseErrorMessage)})
+	}
+
+	return comparator.compareRoot(lengthPath, node.Pointer, lengthPath, expected, length, compareErrors)
+}
+
+// toJsonValue converts a Go value into the same representation that json.Unmarshal creates, for example
+// all numbers become float64. Byte slices and json.RawMessage values are parsed as raw JSON.
+// Values that are already in this representation are used as they are.
+func toJsonValue(value any) (any, error) {
+	switch rawJson := value.(type) {
+	case json.RawMessage:
+		return unmarshalJson(rawJson)
+	case []byte:
+		return unmarshalJson(rawJson)
+	}
+
+	if isJsonValue(value, make(map[container]bool)) {
+		return value, nil
+	}
+
+	rawJson, err := json.Marshal(value)
+	if err != nil {
+		return nil, handleError("unable to convert value to JSON - error [%s]", err)
+	}
+
+	return unmarshalJson(rawJson)
+}
+
+// container identifies a map or a slice by the address & length of its data, like json.Marshal does
+// to detect cycles.
+type container struct {
+	pointer uintptr
+	length  int
+}
+
+// isJsonValue checks if the value only contains the types created by json.Unmarshal, with numbers that can be
+// represented in JSON. Values that contain themselves are not, so that json.Marshal reports the cycle;
+// parents contains the maps & slices that contain the value.
+func isJsonValue(value any, parents map[container]bool) bool {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		key := container{reflect.ValueOf(typedValue).Pointer(), 0}
+		if parents[key] {
+			return false
+		}
+		parents[key] = true
+		defer delete(parents, key)
+
+		for _, fieldValue := range typedValue {
+			if !isJsonValue(fieldValue, parents) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		key := container{reflect.ValueOf(typedValue).Pointer(), len(typedValue)}
+		if parents[key] {
+			return false
+		}
+		parents[key] = true
+		defer delete(parents, key)
+
+		for _, item := range typedValue {
+			if !isJsonValue(item, parents) {
+				return false
+			}
+		}
+		return true
+	case float64:
+		return !math.IsNaN(typedValue) && !math.IsInf(typedValue, 0)
+	case string, bool, nil:
+		return true
+	default:
+		return false
+	}
+}
+
+func lengthOf(value any) (float64, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return float64(utf8.RuneCountInString(typedValue)), true
+	case []any:
+		return float64(len(typedValue)), true
+	case map[string]any:
+		return float64(len(typedValue)), true
+	default:
+		return 0, false
+	}
+}
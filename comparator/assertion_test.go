@@ -0,0 +1,193 @@
+package comparator
+
+import (
+	"encoding/json"
+	"github.com/go-clarum/clarum-json/recorder"
+	"testing"
+)
+
+const assertionActualValue = "{" +
+	"\"status\": \"OK\"," +
+	"\"items\": [" +
+	"{\"id\": 1, \"name\": \"Batarang\", \"price\": 9.99}," +
+	"{\"id\": 2, \"name\": \"Grapnel Gun\", \"price\": 120}," +
+	"{\"id\": 3, \"name\": \"Utility Belt\", \"price\": 49.5}" +
+	"]," +
+	"\"meta\": {\"requestId\": \"a3f1\", \"version\": 2}" +
+	"}"
+
+func TestAssertMatch(t *testing.T) {
+	testAssertions(t, []string{}, "",
+		Assertion{"$.status", "OK"},
+		Assertion{"$.items.length()", 3},
+		Assertion{"$.items[1].name", "Grapnel Gun"},
+		Assertion{"$.items[?@.price > 100].id", 2},
+		Assertion{"$.meta", map[string]any{"requestId": "@ignore@", "version": 2}},
+		Assertion{"$.meta.requestId", "@ignore@"},
+		Assertion{"$.items[0]", json.RawMessage("{\"id\": 1, \"name\": \"Batarang\", \"price\": 9.99}")},
+	)
+}
+
+func TestAssertValueMismatch(t *testing.T) {
+	expectedErrors := []string{
+		"[$.status] - value mismatch - expected [FAILED] but received [OK]",
+		"[$.items.length()] - value mismatch - expected [2] but received [3]",
+	}
+
+	expectedRecorderLog := "\"$.status\": OK, <-- value mismatch - expected [FAILED]\n" +
+		"\"$.items.length()\": 3, <-- value mismatch - expected [2]\n"
+
+	testAssertions(t, expectedErrors, expectedRecorderLog,
+		Assertion{"$.status", "FAILED"},
+		Assertion{"$.items.length()", 2},
+	)
+}
+
+func TestAssertEachSelectedValue(t *testing.T) {
+	expectedErrors := []string{
+		"[$.items[0].price] - type mismatch - expected [string] but found [number]",
+		"[$.items[1].price] - type mismatch - expected [string] but found [number]",
+		"[$.items[2].price] - type mismatch - expected [string] but found [number]",
+	}
+
+	testAssertions(t, expectedErrors, "", Assertion{"$.items[*].price", "9.99"})
+}
+
+func TestAssertObject(t *testing.T) {
+	expectedErrors := []string{
+		"[$.meta.version] - value mismatch - expected [3] but received [2]",
+	}
+
+	expectedRecorderLog := "\"$.meta\": {\n" +
+		"  \"version\": 2, <-- value mismatch - expected [3]\n" +
+		"},\n"
+
+	comparator := NewComparator().
+		StrictObjectCheck(false).
+		Recorder(recorder.NewDefaultRecorder()).
+		Build()
+	recorderResult, err := comparator.Assert([]byte(assertionActualValue),
+		Assertion{"$.meta", map[string]any{"version": 3}})
+
+	checkError(t, err, expectedErrors)
+	checkRecorderLog(t, expectedRecorderLog, recorderResult)
+}
+
+func TestAssertMissingValue(t *testing.T) {
+	expectedErrors := []string{
+		"[$.meta.traceId] - field is missing",
+		"[$.items[?@.price > 1000]] - field is missing",
+	}
+
+	recorderLog := testAssertions(t, expectedErrors, "",
+		Assertion{"$.meta.traceId", "@ignore@"},
+		Assertion{"$.items[?@.price > 1000]", "@ignore@"},
+	)
+
+	if recorderLog != " X-- missing field [$.meta.traceId]\n X-- missing field [$.items[?@.price > 1000]]\n" {
+		t.Error("wrong recorder log: " + recorderLog)
+	}
+
+	_, err := NewComparator().Build().Assert([]byte(assertionActualValue), Assertion{"$.meta.traceId", "abc"})
+	if mismatches := Mismatches(err); len(mismatches) != 1 || mismatches[0].Pointer != "/meta/traceId" {
+		t.Errorf("wrong mismatches: %v", mismatches)
+	}
+}
+
+func TestAssertLengthOfValue(t *testing.T) {
+	expectedErrors := []string{
+		"[$.meta.version.length()] - value has no length - found [number]",
+	}
+
+	testAssertions(t, expectedErrors, "", Assertion{"$.meta.version.length()", 1})
+}
+
+func TestAssertInvalidPath(t *testing.T) {
+	_, err := NewComparator().Build().Assert([]byte(assertionActualValue), Assertion{"$.items[", 1})
+
+	if err == nil || err.Error() != "invalid JSONPath [$.items[] - invalid selector at position 8" {
+		t.Errorf("wrong error: %v", err)
+	}
+	if len(Mismatches(err)) != 0 {
+		t.Error("invalid paths are not mismatches")
+	}
+}
+
+func TestAssertInvalidActualJson(t *testing.T) {
+	expectedErrors := []string{
+		"unable to parse JSON - error [unexpected end of JSON input] - from string [{]",
+	}
+
+	_, err := NewComparator().Build().Assert([]byte("{"), Assertion{"$.status", "OK"})
+	checkError(t, err, expectedErrors)
+}
+
+func testAssertions(t *testing.T, expectedErrors []string, expectedRecorderLog string,
+	assertions ...Assertion) string {
+	comparator := NewComparator().Recorder(recorder.NewDefaultRecorder()).Build()
+	recorderResult, err := comparator.Assert([]byte(assertionActualValue), assertions...)
+
+	checkError(t, err, expectedErrors)
+	checkRecorderLog(t, expectedRecorderLog, recorderResult)
+
+	return recorderResult
+}
+
+func TestCompareAt(t *testing.T) {
+	expectedErrors := []string{
+		"[$.data.customer.age] - value mismatch - expected [37] but received [38]",
+		"[$.data.customer.location] - unexpected field",
+	}
+
+	expectedValue := []byte("{" +
+		"\"name\": \"Bruce\"," +
+		"\"age\": 37" +
+		"}")
+	actualValue := []byte("{" +
+		"\"meta\": {\"requestId\": \"a3f1\"}," +
+		"\"data\": {" +
+		"\"customer\": {" +
+		"\"name\": \"Bruce\"," +
+		"\"age\": 38," +
+		"\"location\": \"Gotham\"" +
+		"}" +
+		"}" +
+		"}")
+
+	comparator := NewComparator().Build()
+	_, err := comparator.CompareAt(expectedValue, actualValue, "$.data.customer")
+
+	checkError(t, err, expectedErrors)
+	if mismatches := Mismatches(err); len(mismatches) != 3 || mismatches[0].Pointer != "/data/customer" {
+		t.Errorf("wrong mismatches: %v", mismatches)
+	}
+}
+
+func TestCompareAtArrayItems(t *testing.T) {
+	expectedErrors := []string{
+		"[$.items[1].name] - value mismatch - expected [Batarang] but received [Grapnel Gun]",
+	}
+
+	testCompareAt(t, []byte("{\"id\": 1, \"name\": \"Batarang\", \"price\": 9.99}"), "$.items[0]", []string{})
+	testCompareAt(t, []byte("{\"name\": \"Batarang\"}"), "$.items[1]", expectedErrors)
+	testCompareAt(t, []byte("3"), "$.items.length()", []string{})
+}
+
+func TestCompareAtMissingPath(t *testing.T) {
+	testCompareAt(t, []byte("{}"), "$.data", []string{"[$.data] - field is missing"})
+}
+
+func TestCompareAtInvalidExpectedJson(t *testing.T) {
+	expectedErrors := []string{
+		"unable to parse JSON - error [unexpected end of JSON input] - from string [{]",
+	}
+
+	testCompareAt(t, []byte("{"), "$.meta", expectedErrors)
+}
+
+func testCompareAt(t *testing.T, expectedValue []byte, path string, expectedErrors []string) {
+	comparator := NewComparator().StrictObjectCheck(false).Build()
+	_, err := comparator.CompareAt(expectedValue, []byte(assertionActualValue), path)
+
+	checkError(t, err, expectedErrors)
+}
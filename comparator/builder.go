@@ -2,6 +2,7 @@ package comparator
 
 import (
 	"github.com/go-clarum/clarum-json/internal"
+	"github.com/go-clarum/clarum-json/jsonpath"
 	"github.com/go-clarum/clarum-json/recorder"
 	"log/slog"
 )
@@ -16,9 +17,9 @@ func NewComparator() *Builder {
 	return &Builder{
 		options{
 			strictObjectCheck: true,
-			// pathsToIgnore:     []string{},
-			logger:   slog.Default(),
-			recorder: internal.NewNoopRecorder(),
+			logger:            slog.Default(),
+			recorder:          internal.NewNoopRecorder(),
+			maxInputSize:      DefaultMaxInputSize,
 		},
 	}
 }
@@ -34,14 +35,27 @@ func (builder *Builder) StrictObjectCheck(check bool) *Builder {
 	return builder
 }
 
-// PathsToIgnore is a list of json paths that the comparator will ignore during validation.
+// PathsToIgnore is a list of JSONPath queries (see [jsonpath.Parse]) that the comparator will ignore during validation.
+// The values they select in the actual or the expected JSON are handled like the '@ignore@' marker, so neither
+// a different value nor a missing or unexpected field is reported for them.
+//
+// The paths apply to comparisons of entire documents. [Comparator.Assert], [Comparator.CompareAt] and
+// [Comparator.CompareStream] return an error when paths to ignore are set.
+// If a path cannot be parsed, the comparisons of the [Comparator] return the parse error.
 // Default is empty.
-/*
 func (builder *Builder) PathsToIgnore(paths ...string) *Builder {
-	builder.pathsToIgnore = append(builder.pathsToIgnore, paths...)
+	for _, path := range paths {
+		parsed, err := jsonpath.Parse(path)
+		if err != nil {
+			if builder.err == nil {
+				builder.err = err
+			}
+			continue
+		}
+		builder.pathsToIgnore = append(builder.pathsToIgnore, parsed)
+	}
 	return builder
 }
-*/
 
 func (builder *Builder) Logger(logger *slog.Logger) *Builder {
 	builder.logger = logger
@@ -55,6 +69,56 @@ func (builder *Builder) Recorder(recorder recorder.Recorder) *Builder {
 	return builder
 }
 
+// MaxInputSize is the maximum number of bytes read from each input of [Comparator.CompareReader].
+// A value of 0 or less removes the limit.
+// Default is [DefaultMaxInputSize].
+func (builder *Builder) MaxInputSize(size int64) *Builder {
+	builder.maxInputSize = size
+	return builder
+}
+
+// MaxDepth is the maximum nesting of objects & arrays that is compared. Deeper JSONs stop the comparison
+// with a [LimitError].
+//
+// The limits are checked while the parsed documents are compared, so they do not protect the parsing itself:
+// except for [Comparator.CompareStream], the inputs are always parsed entirely first. Use [Builder.MaxInputSize]
+// with [Comparator.CompareReader] to limit the size of the inputs that are parsed.
+// Default is 0, which means there is no limit.
+func (builder *Builder) MaxDepth(depth int) *Builder {
+	builder.maxDepth = depth
+	return builder
+}
+
+// MaxArrayLength is the maximum length of the compared arrays. Longer arrays stop the comparison
+// with a [LimitError]. Like [Builder.MaxDepth], it is only checked after the documents were parsed.
+// Default is 0, which means there is no limit.
+func (builder *Builder) MaxArrayLength(length int) *Builder {
+	builder.maxArrayLength = length
+	return builder
+}
+
+// MaxErrors is the maximum number of mismatches collected. When more mismatches are found, the comparison
+// is stopped with a [LimitError].
+// Default is 0, which means there is no limit.
+func (builder *Builder) MaxErrors(errors int) *Builder {
+	builder.maxErrors = errors
+	return builder
+}
+
+// StopAfter stops the comparison once the given number of mismatches was found, which is faster
+// than validating the entire structure when only the result matters. The recorder output is marked as truncated
+// and the returned error contains a [TruncatedError] with the number of checks that were skipped.
+// Default is 0, which means the entire structure is validated.
+func (builder *Builder) StopAfter(mismatches int) *Builder {
+	builder.stopAfter = mismatches
+	return builder
+}
+
+// FailFast stops the comparison at the first mismatch, see [Builder.StopAfter].
+func (builder *Builder) FailFast() *Builder {
+	return builder.StopAfter(1)
+}
+
 func (builder *Builder) Build() *Comparator {
-	return &Comparator{builder.options}
+	return &Comparator{options: builder.options}
 }
@@ -20,4 +20,7 @@ func TestComparatorBuilderDefaults(t *testing.T) {
 	if _, isNoopRecorder := comparator.recorder.(*internal.NoopRecorder); !isNoopRecorder {
 		t.Error("default Recorder must be NoopRecorder")
 	}
+	if comparator.maxInputSize != DefaultMaxInputSize {
+		t.Error("default MaxInputSize must be DefaultMaxInputSize")
+	}
 }
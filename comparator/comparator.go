@@ -1,23 +1,31 @@
 package comparator
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"github.com/go-clarum/clarum-json/internal/path"
+	"github.com/go-clarum/clarum-json/internal"
+	"github.com/go-clarum/clarum-json/internal/matcher"
+	"github.com/go-clarum/clarum-json/jsonpath"
 	"github.com/go-clarum/clarum-json/recorder"
 	"log/slog"
 	"reflect"
 	"strconv"
 )
 
-const ignoreFlag = "@ignore@"
-
 type options struct {
 	strictObjectCheck bool
-	pathsToIgnore     []string
+	pathsToIgnore     []*jsonpath.Path
 	logger            *slog.Logger
 	recorder          recorder.Recorder
+	maxInputSize      int64
+	maxDepth          int
+	maxArrayLength    int
+	maxErrors         int
+	stopAfter         int
+	// err is the first invalid option of the builder, returned by every comparison
+	err error
 }
 
 // Comparator used for comparing JSON structures. It returns detailed errors about how the compared structures do not match.
@@ -25,15 +33,29 @@ type options struct {
 //
 // Always create a comparator using the [NewComparator] builder.
 //
-// The comparator does not fail fast and goes over the entire structure for validation.
+// By default, the comparator does not fail fast and goes over the entire structure for validation,
+// see [Builder.StopAfter] to change this.
 // It is goroutine safe.
 type Comparator struct {
 	options
+	// traversal is only set on the copies of the comparator used for a single comparison
+	traversal *traversal
 }
 
 func (comparator *Comparator) Compare(expected []byte, actual []byte) (string, error) {
-	var expectedJsonObject, actualJsonObject any
+	return comparator.CompareContext(context.Background(), expected, actual)
+}
+
+// CompareContext is like [Comparator.Compare], but stops the comparison when the context is done, returning
+// the error of the context. The comparison is also stopped with a [LimitError] when one of the limits configured with
+// [Builder.MaxDepth], [Builder.MaxArrayLength] or [Builder.MaxErrors] is exceeded.
+// In both cases, the error is joined with the mismatches found until then.
+// The context & the limits only apply to the comparison, the documents are parsed entirely before it starts.
+func (comparator *Comparator) CompareContext(ctx context.Context, expected []byte, actual []byte) (string, error) {
 	comparator.logger.Debug(fmt.Sprintf("json comparator - comparing [%s] to [%s]", expected, actual))
+	if comparator.err != nil {
+		return "", comparator.err
+	}
 
 	expectedJsonObject, err1 := unmarshalJson(expected)
 	if err1 != nil {
@@ -45,173 +67,240 @@ func (comparator *Comparator) Compare(expected []byte, actual []byte) (string, e
 		return "", err2
 	}
 
-	typeOfExpected := reflect.TypeOf(expectedJsonObject)
-	typeOfActual := reflect.TypeOf(actualJsonObject)
-
-	var compareErrors []error
-
-	if typeOfExpected.Kind() != typeOfActual.Kind() {
-		compareErrors = append(compareErrors,
-			errors.New(fmt.Sprintf("root object mismatch - expected [%s] but found [%s]",
-				convertToJsonType(typeOfExpected), convertToJsonType(typeOfActual))))
-	} else if typeOfExpected.Kind() == reflect.Map {
-		compareErrors = comparator.compareJsonMaps("$",
-			expectedJsonObject.(map[string]any), actualJsonObject.(map[string]any),
-			"", compareErrors)
-	} else if typeOfExpected.Kind() == reflect.Slice {
-		compareErrors = comparator.compareSlices("$",
-			expectedJsonObject.([]interface{}), actualJsonObject.([]interface{}),
-			"", compareErrors)
+	return comparator.compare(ctx, expectedJsonObject, actualJsonObject)
+}
+
+// CompareValues compares two Go values, for example a struct that describes the expected response with the
+// response received. The values are converted to JSON first, so the JSON tags of structs are respected,
+// including 'omitempty'. Byte slices and json.RawMessage values are treated as raw JSON. Values that were
+// decoded by json.Unmarshal into an 'any' are compared directly, without converting them again.
+//
+// All features of [Comparator.Compare] are available, for example a field can be ignored
+// by setting it to '@ignore@'. The fields of the expected value that should hold a matcher
+// must therefore be of type string or any.
+func (comparator *Comparator) CompareValues(expected any, actual any) (string, error) {
+	if comparator.err != nil {
+		return "", comparator.err
+	}
+
+	expectedJsonObject, err1 := toJsonValue(expected)
+	if err1 != nil {
+		return "", err1
+	}
+
+	actualJsonObject, err2 := toJsonValue(actual)
+	if err2 != nil {
+		return "", err2
 	}
 
+	// the values are logged once converted, since fmt does not detect values that contain themselves
+	comparator.logger.Debug(fmt.Sprintf("json comparator - comparing values [%+v] to [%+v]", expectedJsonObject,
+		actualJsonObject))
+
+	return comparator.compare(context.Background(), expectedJsonObject, actualJsonObject)
+}
+
+func (comparator *Comparator) compare(ctx context.Context, expected any, actual any) (string, error) {
+	comparison := comparator.begin(ctx)
+	compareErrors := comparison.compareRoot(jsonpath.RootPath, jsonpath.RootPointer, "",
+		comparator.ignorePaths(expected, actual), actual, nil)
+
 	if len(compareErrors) > 0 {
 		comparator.logger.Debug(fmt.Sprintf("json comparator - JSON structures do not match"))
 	} else {
 		comparator.logger.Debug(fmt.Sprintf("json comparator - JSON structures match"))
 	}
 
-	return comparator.recorder.GetLog(), errors.Join(compareErrors...)
+	err := comparison.result(compareErrors)
+	return comparator.recorder.GetLog(), err
 }
 
-func (comparator *Comparator) compareJsonMaps(parentPath string, expected map[string]any, actual map[string]any,
-	logIndent string, compareErrors []error) []error {
+// withoutRecorder returns a copy of the comparator that does not record the comparison.
+func (comparator *Comparator) withoutRecorder() *Comparator {
+	options := comparator.options
+	options.recorder = internal.NewNoopRecorder()
+	return &Comparator{options: options}
+}
+
+// compareRoot compares the values where a comparison starts, which is the root of the documents for Compare.
+// The values can be of any JSON type.
+func (comparator *Comparator) compareRoot(path string, pointer string, fieldName string, expected any, actual any,
+	compareErrors []error) []error {
+	if fieldName != "" {
+		comparator.recorder.AppendFieldName("", fieldName)
+	}
+
+	if matcher.IsIgnore(expected) {
+		comparator.recorder.AppendIgnoreField("", path)
+		internal.RecordPair(comparator.recorder, path, fieldName, expected, actual, "")
+		return compareErrors
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]any:
+		if actualValue, isMap := actual.(map[string]any); isMap {
+			return comparator.compareJsonMaps(path, pointer, fieldName, expectedValue, actualValue, "", compareErrors)
+		}
+	case []any:
+		if actualValue, isSlice := actual.([]any); isSlice {
+			return comparator.compareSlices(path, pointer, fieldName, expectedValue, actualValue, "", compareErrors)
+		}
+	default:
+		if kindOf(expected) == kindOf(actual) {
+			return compareValue(path, pointer, fieldName, expected, actual, comparator.recorder, "", compareErrors)
+		}
+	}
+
+	if !jsonpath.IsRoot(path) {
+		return handleTypeMismatch(path, pointer, fieldName, expected, actual, comparator.recorder, compareErrors)
+	}
+	return handleRootTypeMismatch(path, pointer, fieldName, expected, actual, comparator.recorder, compareErrors)
+}
+
+func (comparator *Comparator) compareJsonMaps(parentPath string, parentPointer string, fieldName string,
+	expected map[string]any, actual map[string]any, logIndent string, compareErrors []error) []error {
+	if !comparator.enter(parentPath, compareErrors) {
+		return compareErrors
+	}
+	defer comparator.leave()
+
 	currIndent := logIndent + "  "
 
-	compareErrors = handleFieldsCheck(parentPath, expected, actual, comparator.strictObjectCheck,
+	compareErrors = handleFieldsCheck(parentPath, parentPointer, fieldName, expected, actual, comparator.strictObjectCheck,
 		comparator.recorder, logIndent, compareErrors)
 
 	for key, expectedValue := range expected {
-		if actualValue, exists := actual[key]; exists {
-			comparator.recorder.AppendFieldName(currIndent, key)
+		if comparator.stopped(compareErrors) {
+			comparator.skip(expectedValue)
+			continue
+		}
+
+		childPath := jsonpath.GetObjectChildPath(parentPath, key)
+		childPointer := jsonpath.GetObjectChildPointer(parentPointer, key)
+
+		actualValue, exists := actual[key]
+		if !exists {
+			compareErrors = handleMissingField(childPath, childPointer, key, expectedValue, currIndent,
+				comparator.recorder, compareErrors)
+			continue
+		}
+
+		comparator.recorder.AppendFieldName(currIndent, key)
 
-			expectedValueType := reflect.TypeOf(expectedValue)
-			actualValueType := reflect.TypeOf(actualValue)
+		if matcher.IsIgnore(expectedValue) {
+			comparator.recorder.AppendIgnoreField(currIndent, parentPath)
+			internal.RecordPair(comparator.recorder, childPath, key, expectedValue, actualValue, "")
+			continue
+		}
 
-			ignoreValueValidation := ignoreValue(expectedValueType, expectedValue)
-			if ignoreValueValidation {
-				comparator.recorder.AppendIgnoreField(currIndent, parentPath)
+		// we only consider JSON types, since the Unmarshal already parsed & checked them
+		switch typedExpectedValue := expectedValue.(type) {
+		case map[string]any:
+			if typedActualValue, isMap := actualValue.(map[string]any); isMap {
+				compareErrors = comparator.compareJsonMaps(childPath, childPointer, key,
+					typedExpectedValue, typedActualValue, currIndent, compareErrors)
 				continue
 			}
-
-			if expectedValueType.Kind() != actualValueType.Kind() {
-				compareErrors = handleTypeMismatch(path.GetObjectChildPath(parentPath, key),
-					expectedValueType, actualValueType, comparator.recorder, compareErrors)
-			} else {
-				// we only consider JSON Kinds, since the Unmarshal already parsed & checked them
-				switch actualValueType.Kind() {
-				case reflect.String:
-					expectedString := expectedValue.(string)
-					actualString := actualValue.(string)
-
-					compareErrors = compareValue(path.GetObjectChildPath(parentPath, key),
-						expectedString != actualString,
-						expectedString, actualString, comparator.recorder, logIndent, compareErrors)
-				case reflect.Float64:
-					compareErrors = compareValue(path.GetObjectChildPath(parentPath, key),
-						expectedValue.(float64) != actualValue.(float64),
-						formatFloat(expectedValue), formatFloat(actualValue), comparator.recorder, logIndent, compareErrors)
-				case reflect.Bool:
-					expectedBool := expectedValue.(bool)
-					actualBool := actualValue.(bool)
-
-					compareErrors = compareValue(path.GetObjectChildPath(parentPath, key),
-						expectedBool != actualBool,
-						strconv.FormatBool(expectedBool), strconv.FormatBool(actualBool), comparator.recorder, logIndent, compareErrors)
-				case reflect.Slice:
-					compareErrors = comparator.compareSlices(path.GetObjectChildPath(parentPath, key),
-						expectedValue.([]interface{}), actualValue.([]interface{}),
-						currIndent, compareErrors)
-				case reflect.Map:
-					compareErrors = comparator.compareJsonMaps(path.GetObjectChildPath(parentPath, key),
-						expectedValue.(map[string]any), actualValue.(map[string]any),
-						currIndent, compareErrors)
-				}
+		case []any:
+			if typedActualValue, isSlice := actualValue.([]any); isSlice {
+				compareErrors = comparator.compareSlices(childPath, childPointer, key,
+					typedExpectedValue, typedActualValue, currIndent, compareErrors)
+				continue
+			}
+		default:
+			if kindOf(expectedValue) == kindOf(actualValue) {
+				compareErrors = compareValue(childPath, childPointer, key, expectedValue, actualValue,
+					comparator.recorder, logIndent, compareErrors)
+				continue
 			}
-		} else {
-			compareErrors = handleMissingField(path.GetObjectChildPath(parentPath, key),
-				key, currIndent, comparator.recorder, compareErrors)
 		}
+
+		compareErrors = handleTypeMismatch(childPath, childPointer, key, expectedValue, actualValue,
+			comparator.recorder, compareErrors)
 	}
 
-	if comparator.strictObjectCheck {
-		compareErrors = handleUnexpectedFields(parentPath, expected, actual, comparator.recorder,
+	if comparator.strictObjectCheck && !comparator.stopped(compareErrors) {
+		compareErrors = handleUnexpectedFields(parentPath, parentPointer, expected, actual, comparator.recorder,
 			currIndent, compareErrors)
 	}
 
 	comparator.recorder.AppendEndObject(logIndent, parentPath)
+	internal.RecordPairEnd(comparator.recorder, parentPath, reflect.Map)
 	return compareErrors
 }
 
 // Arrays in json are represented as slices of type interface because they can contain anything.
 // Each item in the slice can be of any valid JSON type.
-func (comparator *Comparator) compareSlices(parentPath string, expected []interface{}, actual []interface{},
-	currIndent string, compareErrors []error) []error {
+func (comparator *Comparator) compareSlices(parentPath string, parentPointer string, fieldName string,
+	expected []any, actual []any, currIndent string, compareErrors []error) []error {
+	if !comparator.enter(parentPath, compareErrors) {
+		return compareErrors
+	}
+	defer comparator.leave()
+
+	if !comparator.checkArrayLength(parentPath, len(expected), len(actual)) {
+		return compareErrors
+	}
 	comparator.recorder.AppendStartArray(currIndent, parentPath)
 
 	expectedLen := len(expected)
 	actualLen := len(actual)
 	if expectedLen != actualLen {
-		comparator.recorder.AppendValidationErrorSignal(fmt.Sprintf("size mismatch - expected [%d]", expectedLen)).
+		baseErrorMessage := fmt.Sprintf("size mismatch - expected [%d]", expectedLen)
+		comparator.recorder.AppendValidationErrorSignal(baseErrorMessage).
 			AppendEndArray(currIndent, parentPath)
-		return append(compareErrors,
-			errors.New(fmt.Sprintf("[%s] - array size mismatch - expected [%d] but received [%d]", parentPath, expectedLen, actualLen)))
+		internal.RecordPair(comparator.recorder, parentPath, fieldName, expected, actual, baseErrorMessage)
+
+		return append(compareErrors, &Mismatch{ArraySizeMismatch, parentPath, parentPointer, expected, actual,
+			fmt.Sprintf("[%s] - array size mismatch - expected [%d] but received [%d]", parentPath, expectedLen, actualLen)})
 	} else {
 		comparator.recorder.AppendNewLine()
+		internal.RecordPairStart(comparator.recorder, parentPath, fieldName, reflect.Slice, "")
 	}
 
 	valIdent := currIndent + "  "
 	for i, expectedValue := range expected {
-		expectedValueType := reflect.TypeOf(expectedValue)
+		if comparator.stopped(compareErrors) {
+			comparator.skip(expectedValue)
+			continue
+		}
+
 		actualValue := actual[i]
-		actualValueType := reflect.TypeOf(actualValue)
-		jsonPathArray := path.GetArrayIndexPath(parentPath, i)
+		jsonPathArray := jsonpath.GetArrayIndexPath(parentPath, i)
+		pointerArray := jsonpath.GetArrayIndexPointer(parentPointer, i)
 
-		ignoreValueValidation := ignoreValue(expectedValueType, expectedValue)
-		if ignoreValueValidation {
+		if matcher.IsIgnore(expectedValue) {
 			comparator.recorder.AppendIgnoreField(valIdent, jsonPathArray)
+			internal.RecordPair(comparator.recorder, jsonPathArray, "", expectedValue, actualValue, "")
 			continue
 		}
 
-		if expectedValueType.Kind() != actualValueType.Kind() {
-			comparator.recorder.AppendValue(valIdent, jsonPathArray, actualValue, actualValueType.Kind())
-			baseErrorMessage := fmt.Sprintf("value type mismatch - expected [%s] but found [%s]",
-				convertToJsonType(expectedValueType), convertToJsonType(actualValueType))
-
-			compareErrors = append(compareErrors, errors.New(fmt.Sprintf("[%s] - %s", jsonPathArray,
-				baseErrorMessage)))
-			comparator.recorder.AppendValidationErrorSignal(baseErrorMessage)
-		} else {
-			switch actualValueType.Kind() {
-			case reflect.String:
-				expectedString := expectedValue.(string)
-				actualString := actualValue.(string)
-
-				compareErrors = compareValue(jsonPathArray,
-					expectedString != actualString,
-					expectedString, actualString, comparator.recorder, valIdent, compareErrors)
-			case reflect.Float64:
-				compareErrors = compareValue(jsonPathArray,
-					expectedValue.(float64) != actualValue.(float64),
-					formatFloat(expectedValue), formatFloat(actualValue), comparator.recorder, valIdent, compareErrors)
-			case reflect.Bool:
-				expectedBool := expectedValue.(bool)
-				actualBool := actualValue.(bool)
-
-				compareErrors = compareValue(jsonPathArray,
-					expectedBool != actualBool,
-					strconv.FormatBool(expectedBool), strconv.FormatBool(actualBool), comparator.recorder, valIdent, compareErrors)
-			case reflect.Slice:
-				compareErrors = comparator.compareSlices(jsonPathArray,
-					expectedValue.([]interface{}), actualValue.([]interface{}),
-					valIdent, compareErrors)
-			case reflect.Map:
-				compareErrors = comparator.compareJsonMaps(jsonPathArray,
-					expectedValue.(map[string]any), actualValue.(map[string]any),
-					valIdent, compareErrors)
+		switch typedExpectedValue := expectedValue.(type) {
+		case map[string]any:
+			if typedActualValue, isMap := actualValue.(map[string]any); isMap {
+				compareErrors = comparator.compareJsonMaps(jsonPathArray, pointerArray, "",
+					typedExpectedValue, typedActualValue, valIdent, compareErrors)
+				continue
+			}
+		case []any:
+			if typedActualValue, isSlice := actualValue.([]any); isSlice {
+				compareErrors = comparator.compareSlices(jsonPathArray, pointerArray, "",
+					typedExpectedValue, typedActualValue, valIdent, compareErrors)
+				continue
+			}
+		default:
+			if kindOf(expectedValue) == kindOf(actualValue) {
+				compareErrors = compareValue(jsonPathArray, pointerArray, "", expectedValue, actualValue,
+					comparator.recorder, valIdent, compareErrors)
+				continue
 			}
 		}
+
+		compareErrors = handleArrayItemTypeMismatch(jsonPathArray, pointerArray, expectedValue, actualValue,
+			comparator.recorder, valIdent, compareErrors)
 	}
 	comparator.recorder.AppendEndArray(currIndent, parentPath)
+	internal.RecordPairEnd(comparator.recorder, parentPath, reflect.Slice)
 	return compareErrors
 }
 
@@ -219,85 +308,135 @@ func (comparator *Comparator) compareSlices(parentPath string, expected []interf
 // This is why we have to translate Go types into JSON types.
 //
 // json.Unmarshal returns a map[string]interface{} with all the fields of the JSON object:
-// - number is a reflect.Float64
-// - string is a reflect.String
-// - boolean is a reflect.Bool
-// - array is a reflect.Slice
-// - struct is a reflect.Map
-func convertToJsonType(goType reflect.Type) string {
-	switch goType.Kind() {
-	case reflect.Bool:
+// - number is a float64
+// - string is a string
+// - boolean is a bool
+// - array is a []interface{}
+// - object is a map[string]interface{}
+// - null is nil
+func convertToJsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
 		return "boolean"
-	case reflect.Float64:
+	case float64:
 		return "number"
-	case reflect.Map:
+	case string:
+		return "string"
+	case map[string]any:
 		return "object"
-	case reflect.Slice:
+	case []any:
 		return "array"
 	default:
-		return goType.String()
+		return fmt.Sprintf("%T", value)
 	}
 }
 
-func handleFieldsCheck(pathParent string, expected map[string]any, actual map[string]any, strictObjectCheck bool,
-	recorder recorder.Recorder, indent string, compareErrors []error) []error {
+func handleFieldsCheck(pathParent string, pointerParent string, fieldName string, expected map[string]any,
+	actual map[string]any, strictObjectCheck bool, recorder recorder.Recorder, indent string,
+	compareErrors []error) []error {
 	if strictObjectCheck && len(expected) != len(actual) {
+		baseErrorMessage := "number of fields does not match"
 		recorder.AppendStartObject(indent, pathParent).
-			AppendValidationErrorSignal("number of fields does not match")
+			AppendValidationErrorSignal(baseErrorMessage)
+		internal.RecordPairStart(recorder, pathParent, fieldName, reflect.Map, baseErrorMessage)
 
-		compareErrors = append(compareErrors,
-			errors.New(fmt.Sprintf("[%s] - number of fields does not match", pathParent)))
+		compareErrors = append(compareErrors, &Mismatch{FieldCountMismatch, pathParent, pointerParent,
+			len(expected), len(actual), fmt.Sprintf("[%s] - %s", pathParent, baseErrorMessage)})
 	} else {
 		recorder.AppendStartObject(indent, pathParent).AppendNewLine()
+		internal.RecordPairStart(recorder, pathParent, fieldName, reflect.Map, "")
 	}
 	return compareErrors
 }
 
-func handleUnexpectedFields(pathParent string, expected map[string]any, actual map[string]any,
-	recorder recorder.Recorder, indent string, compareErrors []error) []error {
-	for key := range actual {
+func handleUnexpectedFields(pathParent string, pointerParent string, expected map[string]any,
+	actual map[string]any, recorder recorder.Recorder, indent string, compareErrors []error) []error {
+	for key, actualValue := range actual {
 		if _, exists := expected[key]; !exists {
+			childPath := jsonpath.GetObjectChildPath(pathParent, key)
 			recorder.AppendFieldName(indent, key).
 				AppendValidationErrorSignal("unexpected field")
+			internal.RecordUnexpectedPair(recorder, childPath, key, actualValue)
 
-			compareErrors = append(compareErrors,
-				errors.New(fmt.Sprintf("[%s] - unexpected field", path.GetObjectChildPath(pathParent, key))))
+			compareErrors = append(compareErrors, &Mismatch{UnexpectedField, childPath,
+				jsonpath.GetObjectChildPointer(pointerParent, key), nil, actualValue,
+				fmt.Sprintf("[%s] - unexpected field", childPath)})
 		}
 	}
 
 	return compareErrors
 }
 
-func handleTypeMismatch(path string, expectedValueType reflect.Type, actualValueType reflect.Type,
+func handleTypeMismatch(path string, pointer string, fieldName string, expectedValue any, actualValue any,
 	recorder recorder.Recorder, compareErrors []error) []error {
 
 	baseErrorMessage := fmt.Sprintf("type mismatch - expected [%s] but found [%s]",
-		convertToJsonType(expectedValueType), convertToJsonType(actualValueType))
+		convertToJsonType(expectedValue), convertToJsonType(actualValue))
 
-	compareErrors = append(compareErrors, errors.New(fmt.Sprintf("[%s] - %s", path, baseErrorMessage)))
+	compareErrors = append(compareErrors, &Mismatch{TypeMismatch, path, pointer, expectedValue, actualValue,
+		fmt.Sprintf("[%s] - %s", path, baseErrorMessage)})
 	recorder.AppendValidationErrorSignal(baseErrorMessage)
+	internal.RecordPair(recorder, path, fieldName, expectedValue, actualValue, baseErrorMessage)
 
 	return compareErrors
 }
 
-func compareValue(path string, mismatch bool, expectedValue string, actualValue string, recorder recorder.Recorder,
-	indent string, compareErrors []error) []error {
-	recorder.AppendValue(indent, path, actualValue, reflect.String)
+func handleRootTypeMismatch(path string, pointer string, fieldName string, expected any, actual any,
+	recorder recorder.Recorder, compareErrors []error) []error {
+	baseErrorMessage := fmt.Sprintf("root object mismatch - expected [%s] but found [%s]",
+		convertToJsonType(expected), convertToJsonType(actual))
+
+	compareErrors = append(compareErrors, &Mismatch{TypeMismatch, path, pointer, expected, actual,
+		baseErrorMessage})
+	internal.RecordPair(recorder, path, fieldName, expected, actual, baseErrorMessage)
+
+	return compareErrors
+}
 
-	if mismatch {
-		compareErrors = append(compareErrors,
-			errors.New(fmt.Sprintf("[%s] - value mismatch - expected [%s] but received [%s]", path, expectedValue, actualValue)))
-		recorder.AppendValidationErrorSignal(fmt.Sprintf("value mismatch - expected [%s]", expectedValue))
+func handleArrayItemTypeMismatch(path string, pointer string, expectedValue any, actualValue any,
+	recorder recorder.Recorder, indent string, compareErrors []error) []error {
+	recorder.AppendValue(indent, path, actualValue, kindOf(actualValue))
+	baseErrorMessage := fmt.Sprintf("value type mismatch - expected [%s] but found [%s]",
+		convertToJsonType(expectedValue), convertToJsonType(actualValue))
+
+	compareErrors = append(compareErrors, &Mismatch{TypeMismatch, path, pointer,
+		expectedValue, actualValue, fmt.Sprintf("[%s] - %s", path, baseErrorMessage)})
+	recorder.AppendValidationErrorSignal(baseErrorMessage)
+	internal.RecordPair(recorder, path, "", expectedValue, actualValue, baseErrorMessage)
+
+	return compareErrors
+}
+
+// compareValue compares JSON values that have no children: strings, numbers & booleans.
+// Both values must be of the same kind.
+func compareValue(path string, pointer string, fieldName string, expectedValue any, actualValue any, recorder recorder.Recorder,
+	indent string, compareErrors []error) []error {
+	actualString := formatValue(actualValue)
+	recorder.AppendValue(indent, path, actualString, reflect.String)
+
+	if expectedValue != actualValue {
+		expectedString := formatValue(expectedValue)
+		baseErrorMessage := fmt.Sprintf("value mismatch - expected [%s]", expectedString)
+		compareErrors = append(compareErrors, &Mismatch{ValueMismatch, path, pointer, expectedValue, actualValue,
+			fmt.Sprintf("[%s] - value mismatch - expected [%s] but received [%s]", path, expectedString, actualString)})
+		recorder.AppendValidationErrorSignal(baseErrorMessage)
+		internal.RecordPair(recorder, path, fieldName, expectedValue, actualValue, baseErrorMessage)
 	} else {
 		recorder.AppendNewLine()
+		internal.RecordPair(recorder, path, fieldName, expectedValue, actualValue, "")
 	}
 
 	return compareErrors
 }
 
-func handleMissingField(path string, fieldName string, indent string, recorder recorder.Recorder, compareErrors []error) []error {
-	compareErrors = append(compareErrors, errors.New(fmt.Sprintf("[%s] - field is missing", path)))
+func handleMissingField(path string, pointer string, fieldName string, expectedValue any, indent string,
+	recorder recorder.Recorder, compareErrors []error) []error {
+	compareErrors = append(compareErrors, &Mismatch{MissingField, path, pointer, expectedValue, nil,
+		fmt.Sprintf("[%s] - field is missing", path)})
 	recorder.AppendMissingFieldErrorSignal(indent, fieldName)
+	internal.RecordMissingPair(recorder, path, fieldName, expectedValue)
 
 	return compareErrors
 }
@@ -312,17 +451,44 @@ func unmarshalJson(rawJson []byte) (any, error) {
 	return result, nil
 }
 
+// formatValue returns the representation of a string, number or boolean used in error messages.
+func formatValue(value any) string {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue
+	case float64:
+		return formatFloat(typedValue)
+	case bool:
+		return strconv.FormatBool(typedValue)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%v", typedValue)
+	}
+}
+
 // We have to trim trailing zeroes from the parsed float64 number before logging them.
 func formatFloat(expectedValue any) string {
 	return strconv.FormatFloat(expectedValue.(float64), 'f', -1, 64)
 }
 
-func ignoreValue(valueType reflect.Type, value any) bool {
-	if valueType.Kind() == reflect.String {
-		return value == ignoreFlag
+// kindOf returns the kind of a value created by json.Unmarshal, which is also the kind passed to the recorder.
+// JSON null has no type, so its kind is reflect.Invalid.
+func kindOf(value any) reflect.Kind {
+	switch value.(type) {
+	case string:
+		return reflect.String
+	case float64:
+		return reflect.Float64
+	case bool:
+		return reflect.Bool
+	case map[string]any:
+		return reflect.Map
+	case []any:
+		return reflect.Slice
+	default:
+		return reflect.Invalid
 	}
-
-	return false
 }
 
 func handleError(format string, a ...any) error {
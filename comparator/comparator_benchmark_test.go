@@ -0,0 +1,113 @@
+package comparator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"testing"
+)
+
+var benchmarkShapes = []struct {
+	name     string
+	document func(size int) []byte
+	sizes    []int
+}{
+	{"wide", wideDocument, []int{100, 1_000, 10_000}},
+	{"deep", deepDocument, []int{10, 100, 1_000}},
+	{"long", longDocument, []int{1_000, 10_000, 100_000}},
+}
+
+// BenchmarkCompare measures the entire comparison, including the parsing of the documents.
+func BenchmarkCompare(b *testing.B) {
+	comparator := NewComparator().Build()
+
+	forEachBenchmarkShape(b, func(b *testing.B, document []byte) {
+		b.SetBytes(int64(2 * len(document)))
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_, _ = comparator.Compare(document, document)
+		}
+	})
+}
+
+// BenchmarkEqual measures the comparison that only checks if the documents are equal.
+func BenchmarkEqual(b *testing.B) {
+	comparator := NewComparator().Build()
+
+	forEachBenchmarkShape(b, func(b *testing.B, document []byte) {
+		b.SetBytes(int64(2 * len(document)))
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = comparator.Equal(document, document)
+		}
+	})
+}
+
+// BenchmarkExpectationMatch measures the comparison with an expected JSON that is parsed only once.
+func BenchmarkExpectationMatch(b *testing.B) {
+	comparator := NewComparator().Build()
+
+	forEachBenchmarkShape(b, func(b *testing.B, document []byte) {
+		expectation, _ := comparator.Compile(document)
+		b.SetBytes(int64(len(document)))
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = expectation.Match(document)
+		}
+	})
+}
+
+// BenchmarkCompareTraversal measures only the traversal of already parsed documents.
+func BenchmarkCompareTraversal(b *testing.B) {
+	comparator := NewComparator().Build()
+
+	forEachBenchmarkShape(b, func(b *testing.B, document []byte) {
+		expected, _ := unmarshalJson(document)
+		actual, _ := unmarshalJson(document)
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = comparator.begin(context.Background()).compareRoot(jsonpath.RootPath, jsonpath.RootPointer, "", expected, actual, nil)
+		}
+	})
+}
+
+func forEachBenchmarkShape(b *testing.B, benchmark func(b *testing.B, document []byte)) {
+	for _, shape := range benchmarkShapes {
+		for _, size := range shape.sizes {
+			document := shape.document(size)
+			b.Run(fmt.Sprintf("%s=%d", shape.name, size), func(b *testing.B) {
+				benchmark(b, document)
+			})
+		}
+	}
+}
+
+// wideDocument creates an object with many fields of all types.
+func wideDocument(fields int) []byte {
+	var document bytes.Buffer
+	document.WriteString("{")
+	for i := 0; i < fields; i++ {
+		if i > 0 {
+			document.WriteString(", ")
+		}
+		switch i % 4 {
+		case 0:
+			_, _ = fmt.Fprintf(&document, "\"field%d\": \"value %d\"", i, i)
+		case 1:
+			_, _ = fmt.Fprintf(&document, "\"field%d\": %d.5", i, i)
+		case 2:
+			_, _ = fmt.Fprintf(&document, "\"field%d\": true", i)
+		default:
+			_, _ = fmt.Fprintf(&document, "\"field%d\": null", i)
+		}
+	}
+	document.WriteString("}")
+	return document.Bytes()
+}
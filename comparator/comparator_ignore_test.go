@@ -1,6 +1,7 @@
 package comparator
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -129,3 +130,66 @@ func TestIgnoreValueInRootArray(t *testing.T) {
 
 	testComparator(t, expectedValue, actualValue, []string{}, expectedRecorderLog)
 }
+
+func TestIgnorePaths(t *testing.T) {
+	expectedValue := []byte("{\"name\": \"Bruce\", \"meta\": {\"id\": 1}, \"old\": true, \"aliases\": [{\"id\": 1, \"name\": \"Batman\"}]}")
+	actualValue := []byte("{\"name\": \"Bruce\", \"meta\": {\"id\": 2}, \"new\": true, \"aliases\": [{\"id\": 2, \"name\": \"Batman\"}]}")
+
+	comparator := NewComparator().PathsToIgnore("$.meta", "$.old", "$.new", "$..id").Build()
+
+	if _, err := comparator.Compare(expectedValue, actualValue); err != nil {
+		t.Errorf("no errors expected: %s", err)
+	}
+	if !comparator.Equal(expectedValue, actualValue) {
+		t.Error("the JSONs must be equal")
+	}
+
+	expectation, _ := comparator.Compile(expectedValue)
+	for i := 0; i < 2; i++ {
+		if err := expectation.Match(actualValue); err != nil {
+			t.Errorf("no errors expected: %s", err)
+		}
+	}
+}
+
+func TestIgnorePathsOnlyIgnoresSelectedValues(t *testing.T) {
+	expectedValue := []byte("{\"name\": \"Bruce\", \"meta\": {\"id\": 1}}")
+	actualValue := []byte("{\"name\": \"Dick\", \"meta\": {\"id\": 2}}")
+
+	_, err := NewComparator().PathsToIgnore("$.meta.id").Build().Compare(expectedValue, actualValue)
+
+	checkError(t, err, []string{"[$.name] - value mismatch - expected [Bruce] but received [Dick]"})
+	if strings.Contains(err.Error(), "$.meta") {
+		t.Errorf("[$.meta.id] must be ignored: %s", err)
+	}
+}
+
+func TestIgnorePathsNotSupported(t *testing.T) {
+	comparator := NewComparator().PathsToIgnore("$.meta").Build()
+
+	if _, err := comparator.Assert([]byte("{}"), Assertion{"$.name", "Bruce"}); err == nil {
+		t.Error("Assert must not support paths to ignore")
+	}
+	if err := comparator.CompareStream(strings.NewReader("{}"), strings.NewReader("{}")); err == nil {
+		t.Error("CompareStream must not support paths to ignore")
+	}
+}
+
+func TestIgnorePathsInvalid(t *testing.T) {
+	comparator := NewComparator().PathsToIgnore("$.meta", "$.").Build()
+
+	_, err := comparator.Compare([]byte("{}"), []byte("{}"))
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid JSONPath [$.]") {
+		t.Errorf("expected an invalid path error but found %v", err)
+	}
+	if _, err := comparator.Compile([]byte("{}")); err == nil {
+		t.Error("Compile must return the invalid path error")
+	}
+	if err := comparator.CompareStream(strings.NewReader("{}"), strings.NewReader("{}")); err == nil ||
+		!strings.HasPrefix(err.Error(), "invalid JSONPath [$.]") {
+		t.Error("CompareStream must return the invalid path error")
+	}
+	if comparator.Equal([]byte("{}"), []byte("{}")) {
+		t.Error("JSONs must not be equal with an invalid path")
+	}
+}
@@ -0,0 +1,136 @@
+package comparator
+
+import (
+	"github.com/go-clarum/clarum-json/recorder"
+	"strings"
+	"testing"
+)
+
+func TestSideBySideMatch(t *testing.T) {
+	expectedValue := []byte("{" +
+		"\"aliases\": [\"Batman\", 1007, true]" +
+		"}")
+	actualValue := []byte("{" +
+		"\"aliases\": [\"Batman\", 1007, true]" +
+		"}")
+
+	expectedRecorderLog := "{                {\n" +
+		"  \"aliases\": [     \"aliases\": [\n" +
+		"    \"Batman\",        \"Batman\",\n" +
+		"    1007,            1007,\n" +
+		"    true,            true,\n" +
+		"  ],               ],\n" +
+		"}                }\n"
+
+	testSideBySideComparator(t, expectedValue, actualValue, []string{}, expectedRecorderLog)
+}
+
+func TestSideBySideValueMismatch(t *testing.T) {
+	expectedErrors := []string{
+		"[$.aliases[0]] - value mismatch - expected [Batman] but received [Robin]",
+	}
+
+	expectedValue := []byte("{" +
+		"\"aliases\": [\"Batman\", \"@ignore@\"]" +
+		"}")
+	actualValue := []byte("{" +
+		"\"aliases\": [\"Robin\", \"Bruce\"]" +
+		"}")
+
+	expectedRecorderLog := "{                 {\n" +
+		"  \"aliases\": [      \"aliases\": [\n" +
+		"    \"Batman\",   |     \"Robin\",\n" +
+		"    \"@ignore@\",       \"Bruce\",\n" +
+		"  ],                ],\n" +
+		"}                 }\n"
+
+	testSideBySideComparator(t, expectedValue, actualValue, expectedErrors, expectedRecorderLog)
+}
+
+func TestSideBySideTypeMismatch(t *testing.T) {
+	expectedErrors := []string{
+		"[$.location] - type mismatch - expected [string] but found [object]",
+	}
+
+	expectedValue := []byte("{" +
+		"\"location\": \"Mountain Drive\"" +
+		"}")
+	actualValue := []byte("{" +
+		"\"location\": {" +
+		"\"street\": \"Mountain Drive\"" +
+		"}" +
+		"}")
+
+	expectedRecorderLog := "{                                 {\n" +
+		"  \"location\": \"Mountain Drive\", |   \"location\": {\n" +
+		"                                >     \"street\": \"Mountain Drive\"\n" +
+		"                                >   },\n" +
+		"}                                 }\n"
+
+	testSideBySideComparator(t, expectedValue, actualValue, expectedErrors, expectedRecorderLog)
+}
+
+func TestSideBySideArraySizeMismatch(t *testing.T) {
+	expectedErrors := []string{
+		"[$] - array size mismatch - expected [1] but received [2]",
+	}
+
+	expectedValue := []byte("[\"Batman\"]")
+	actualValue := []byte("[\"Batman\", \"Robin\"]")
+
+	expectedRecorderLog := "[          | [\n" +
+		"  \"Batman\" |   \"Batman\",\n" +
+		"]          |   \"Robin\"\n" +
+		"           > ]\n"
+
+	testSideBySideComparator(t, expectedValue, actualValue, expectedErrors, expectedRecorderLog)
+}
+
+func TestSideBySideMissingAndUnexpectedFields(t *testing.T) {
+	expectedErrors := []string{
+		"[$] - number of fields does not match",
+		"[$.location] - field is missing",
+		"[$.address] - unexpected field",
+	}
+
+	expectedValue := []byte("{" +
+		"\"active\": true," +
+		"\"location\": {" +
+		"\"street\": \"Mountain Drive\"" +
+		"}" +
+		"}")
+	actualValue := []byte("{" +
+		"\"active\": true," +
+		"\"address\": \"Mountain Drive\"," +
+		"\"name\": \"Bruce\"" +
+		"}")
+
+	recorderLog := testSideBySideComparator(t, expectedValue, actualValue, expectedErrors, "")
+
+	expectedLines := []string{
+		"{                              | {\n",
+		"  \"location\": {                <\n",
+		"    \"street\": \"Mountain Drive\" <\n",
+		"  },                           <\n",
+		"  \"active\": true,                  \"active\": true,\n",
+		"                               >   \"address\": \"Mountain Drive\",\n",
+		"                               >   \"name\": \"Bruce\",\n",
+		"}                                }\n",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(recorderLog, line) {
+			t.Errorf("missing line: %s", line)
+		}
+	}
+}
+
+func testSideBySideComparator(t *testing.T, expectedValue []byte, actualValue []byte, expectedErrors []string,
+	expectedRecorderLog string) string {
+	comparator := NewComparator().Recorder(recorder.NewSideBySideRecorder()).Build()
+	recorderResult, err := comparator.Compare(expectedValue, actualValue)
+
+	checkError(t, err, expectedErrors)
+	checkRecorderLog(t, expectedRecorderLog, recorderResult)
+
+	return recorderResult
+}
@@ -376,6 +376,41 @@ func TestKindValidationArrayType(t *testing.T) {
 
 }
 
+func TestEscapedFieldPath(t *testing.T) {
+	expectedErrors := []string{
+		"[$['location.street']] - value mismatch - expected [Mountain Drive] but received [Park Row]",
+	}
+
+	expectedValue := []byte("{" +
+		"\"location.street\": \"Mountain Drive\"" +
+		"}")
+	actualValue := []byte("{" +
+		"\"location.street\": \"Park Row\"" +
+		"}")
+
+	expectedRecorderLog := "{\n" +
+		"  \"location.street\": Park Row, <-- value mismatch - expected [Mountain Drive]\n" +
+		"}\n"
+
+	testComparator(t, expectedValue, actualValue, expectedErrors, expectedRecorderLog)
+}
+
+func TestNullValues(t *testing.T) {
+	testComparator(t, []byte("{\"gadgets\": [null, 1]}"), []byte("{\"gadgets\": [null, 1]}"), []string{},
+		"{\n  \"gadgets\": [\n    null,\n    1,\n  ],\n}\n")
+	testComparator(t, []byte("{\"name\": null}"), []byte("{\"name\": null}"), []string{},
+		"{\n  \"name\": null,\n}\n")
+
+	testComparator(t, []byte("{\"name\": null}"), []byte("{\"name\": \"Bruce\"}"),
+		[]string{"[$.name] - type mismatch - expected [null] but found [string]"}, "")
+
+	testComparator(t, []byte("[null]"), []byte("[true]"),
+		[]string{"[$[0]] - value type mismatch - expected [null] but found [boolean]"}, "")
+
+	testComparator(t, []byte("null"), []byte("{}"),
+		[]string{"root object mismatch - expected [null] but found [object]"}, "")
+}
+
 func checkError(t *testing.T, err error, expectedErrors []string) {
 	if len(expectedErrors) == 0 && err != nil { // no error expected
 		t.Error(err)
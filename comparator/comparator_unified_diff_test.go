@@ -0,0 +1,104 @@
+package comparator
+
+import (
+	"github.com/go-clarum/clarum-json/recorder"
+	"testing"
+)
+
+func TestUnifiedDiffMatch(t *testing.T) {
+	expectedValue := []byte("{" +
+		"\"active\": true," +
+		"\"timestamp\": \"@ignore@\"" +
+		"}")
+	actualValue := []byte("{" +
+		"\"timestamp\": \"2024-01-03 23:42:00\"," +
+		"\"active\": true" +
+		"}")
+
+	testUnifiedDiffComparator(t, NewComparator(), expectedValue, actualValue, []string{}, "")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	expectedErrors := []string{
+		"[$.age] - value mismatch - expected [37] but received [38]",
+		"[$.location.street] - field is missing",
+		"[$.location.address] - unexpected field",
+	}
+
+	expectedValue := []byte("{" +
+		"\"name\": \"Bruce\"," +
+		"\"age\": 37," +
+		"\"location\": {" +
+		"\"street\": \"Mountain Drive\"," +
+		"\"timestamp\": \"@ignore@\"" +
+		"}," +
+		"\"aliases\": [\"Batman\"]" +
+		"}")
+	actualValue := []byte("{" +
+		"\"name\": \"Bruce\"," +
+		"\"age\": 38," +
+		"\"location\": {" +
+		"\"address\": \"Mountain Drive\"," +
+		"\"timestamp\": \"2024-01-03 23:42:00\"" +
+		"}," +
+		"\"aliases\": [\"Batman\", \"Robin\"]" +
+		"}")
+
+	expectedRecorderLog := "--- expected\n" +
+		"+++ actual\n" +
+		"@@ -1,10 +1,11 @@\n" +
+		" {\n" +
+		"-  \"age\": 37,\n" +
+		"+  \"age\": 38,\n" +
+		"   \"aliases\": [\n" +
+		"-    \"Batman\"\n" +
+		"+    \"Batman\",\n" +
+		"+    \"Robin\"\n" +
+		"   ],\n" +
+		"   \"location\": {\n" +
+		"-    \"street\": \"Mountain Drive\",\n" +
+		"+    \"address\": \"Mountain Drive\",\n" +
+		"     \"timestamp\": \"@ignore@\"\n" +
+		"   },\n" +
+		"   \"name\": \"Bruce\"\n"
+
+	testUnifiedDiffComparator(t, NewComparator(), expectedValue, actualValue, expectedErrors, expectedRecorderLog)
+}
+
+func TestUnifiedDiffNotStrict(t *testing.T) {
+	expectedErrors := []string{
+		"[$[0].active] - value mismatch - expected [true] but received [false]",
+	}
+
+	expectedValue := []byte("[{" +
+		"\"active\": true" +
+		"}]")
+	actualValue := []byte("[{" +
+		"\"active\": false," +
+		"\"someOther\": \"some value\"" +
+		"}]")
+
+	expectedRecorderLog := "--- expected\n" +
+		"+++ actual\n" +
+		"@@ -1,5 +1,5 @@\n" +
+		" [\n" +
+		"   {\n" +
+		"-    \"active\": true\n" +
+		"+    \"active\": false\n" +
+		"   }\n" +
+		" ]\n"
+
+	testUnifiedDiffComparator(t, NewComparator().StrictObjectCheck(false), expectedValue, actualValue,
+		expectedErrors, expectedRecorderLog)
+}
+
+func testUnifiedDiffComparator(t *testing.T, builder *Builder, expectedValue []byte, actualValue []byte,
+	expectedErrors []string, expectedRecorderLog string) {
+	comparator := builder.Recorder(recorder.NewUnifiedDiffRecorder()).Build()
+	recorderResult, err := comparator.Compare(expectedValue, actualValue)
+
+	checkError(t, err, expectedErrors)
+	if recorderResult != expectedRecorderLog {
+		t.Error("Recorder log does not match:\n" + recorderResult)
+	}
+}
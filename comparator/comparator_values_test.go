@@ -0,0 +1,108 @@
+package comparator
+
+import (
+	"encoding/json"
+	"github.com/go-clarum/clarum-json/recorder"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type hero struct {
+	Name     string   `json:"name"`
+	Alias    string   `json:"alias,omitempty"`
+	Age      int      `json:"age"`
+	Gadgets  []string `json:"gadgets"`
+	Location any      `json:"location,omitempty"`
+	secret   string
+}
+
+func TestCompareValuesMatch(t *testing.T) {
+	expected := hero{Name: "Bruce Wayne", Alias: "Batman", Age: 30, Gadgets: []string{"batarang"}, secret: "cave"}
+	actual := hero{Name: "Bruce Wayne", Alias: "Batman", Age: 30, Gadgets: []string{"batarang"}}
+
+	testCompareValues(t, expected, actual, []string{}, "")
+}
+
+func TestCompareValuesWithRawJson(t *testing.T) {
+	expected := hero{Name: "Bruce Wayne", Age: 30, Gadgets: []string{"batarang"}, Location: "@ignore@"}
+	actual := []byte("{\"name\": \"Bruce Wayne\", \"age\": 30, \"gadgets\": [\"batarang\"], \"location\": \"Gotham\"}")
+
+	testCompareValues(t, expected, actual, []string{}, "")
+	testCompareValues(t, expected, json.RawMessage(actual), []string{}, "")
+}
+
+func TestCompareValuesMismatch(t *testing.T) {
+	expected := hero{Name: "Bruce Wayne", Alias: "Batman", Age: 30, Gadgets: []string{"batarang", "grapnel gun"}}
+	actual := map[string]any{"name": "Bruce Wayne", "age": 31, "gadgets": []string{"batarang", "utility belt"}}
+
+	expectedErrors := []string{
+		"[$] - number of fields does not match",
+		"[$.alias] - field is missing",
+		"[$.age] - value mismatch - expected [30] but received [31]",
+		"[$.gadgets[1]] - value mismatch - expected [grapnel gun] but received [utility belt]",
+	}
+
+	testCompareValues(t, expected, actual, expectedErrors, "")
+}
+
+func TestCompareValuesOmitEmpty(t *testing.T) {
+	expected := hero{Name: "Bruce Wayne", Gadgets: []string{}}
+	actual := hero{Name: "Bruce Wayne", Alias: "Batman", Gadgets: []string{}}
+
+	expectedErrors := []string{
+		"[$.alias] - unexpected field",
+	}
+
+	testCompareValues(t, expected, actual, expectedErrors, "")
+}
+
+func TestCompareValuesDecodedJson(t *testing.T) {
+	var decoded map[string]any
+	_ = json.Unmarshal([]byte("{\"name\": \"Bruce Wayne\", \"age\": 30, \"gadgets\": [\"batarang\", null, true]}"), &decoded)
+
+	// decoded values are not converted again, other values are
+	if converted, _ := toJsonValue(decoded); reflect.ValueOf(converted).Pointer() != reflect.ValueOf(decoded).Pointer() {
+		t.Error("a decoded JSON must be used as it is")
+	}
+	if converted, _ := toJsonValue(map[string]any{"age": 30}); converted.(map[string]any)["age"] != 30.0 {
+		t.Errorf("the value must be converted: %v", converted)
+	}
+
+	testCompareValues(t, decoded, hero{Name: "Bruce Wayne", Age: 30, Gadgets: []string{"batarang", "", ""}},
+		[]string{"[$.gadgets[1]] - value type mismatch - expected [null] but found [string]"}, "")
+}
+
+func TestCompareValuesInvalidValue(t *testing.T) {
+	comparator := NewComparator().Build()
+
+	_, err := comparator.CompareValues(map[string]any{"callback": func() {}}, map[string]any{})
+
+	checkError(t, err, []string{"unable to convert value to JSON - error [json: unsupported type: func()]"})
+}
+
+func TestCompareValuesCyclicValue(t *testing.T) {
+	comparator := NewComparator().Build()
+
+	cyclicMap := map[string]any{}
+	cyclicMap["self"] = cyclicMap
+	_, err := comparator.CompareValues(cyclicMap, map[string]any{})
+	if err == nil || !strings.Contains(err.Error(), "encountered a cycle") {
+		t.Errorf("expected a cycle error but found %v", err)
+	}
+
+	cyclicSlice := []any{nil}
+	cyclicSlice[0] = cyclicSlice
+	_, err = comparator.CompareValues([]any{}, cyclicSlice)
+	if err == nil || !strings.Contains(err.Error(), "encountered a cycle") {
+		t.Errorf("expected a cycle error but found %v", err)
+	}
+}
+
+func testCompareValues(t *testing.T, expected any, actual any, expectedErrors []string, expectedRecorderLog string) {
+	comparator := NewComparator().StrictObjectCheck(true).Recorder(recorder.NewDefaultRecorder()).Build()
+	recorderResult, err := comparator.CompareValues(expected, actual)
+
+	checkError(t, err, expectedErrors)
+	checkRecorderLog(t, expectedRecorderLog, recorderResult)
+}
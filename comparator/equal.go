@@ -0,0 +1,100 @@
+package comparator
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-clarum/clarum-json/internal/matcher"
+)
+
+var defaultComparator = NewComparator().Build()
+
+// Equal checks if the actual JSON matches the expected one, using a comparator with the default options.
+// See [Comparator.Equal].
+func Equal(expected []byte, actual []byte) bool {
+	return defaultComparator.Equal(expected, actual)
+}
+
+// Equal checks if the actual JSON matches the expected one with the same rules as [Comparator.Compare],
+// like the '@ignore@' marker and the strict object check. It is meant for loops that only need the result,
+// like polling an endpoint until it returns the expected JSON, so it stops at the first difference and neither creates
+// errors nor uses the recorder. Invalid JSON is never equal, and neither is any JSON if an option of the comparator
+// is invalid. JSONs that exceed [Builder.MaxDepth] or [Builder.MaxArrayLength] are not equal either,
+// as [Comparator.Compare] stops with a [LimitError] for them.
+func (comparator *Comparator) Equal(expected []byte, actual []byte) bool {
+	if comparator.err != nil {
+		return false
+	}
+
+	var expectedJsonObject, actualJsonObject any
+	if json.Unmarshal(expected, &expectedJsonObject) != nil || json.Unmarshal(actual, &actualJsonObject) != nil {
+		return false
+	}
+
+	equal, _ := comparator.equal(context.Background(), comparator.ignorePaths(expectedJsonObject, actualJsonObject),
+		actualJsonObject)
+	return equal
+}
+
+// equal checks the values within the limits of the comparator and stops when the context is done.
+// The error is the reason why the check was stopped, if it was.
+func (comparator *Comparator) equal(ctx context.Context, expected any, actual any) (bool, error) {
+	comparison := comparator.begin(ctx)
+	return comparison.equalValues(expected, actual), comparison.traversal.err
+}
+
+func (comparator *Comparator) equalValues(expected any, actual any) bool {
+	if matcher.IsIgnore(expected) {
+		return true
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]any:
+		actualValue, isMap := actual.(map[string]any)
+		return isMap && comparator.equalMaps(expectedValue, actualValue)
+	case []any:
+		actualValue, isSlice := actual.([]any)
+		return isSlice && comparator.equalSlices(expectedValue, actualValue)
+	default:
+		// strings, numbers, booleans & null are comparable
+		return expected == actual
+	}
+}
+
+func (comparator *Comparator) equalMaps(expected map[string]any, actual map[string]any) bool {
+	// the paths are only needed for the errors, which are not returned
+	if !comparator.enter("", nil) {
+		return false
+	}
+	defer comparator.leave()
+
+	// every expected field must exist, so the actual object has no other fields if both have the same size
+	if comparator.strictObjectCheck && len(expected) != len(actual) {
+		return false
+	}
+
+	for key, expectedValue := range expected {
+		actualValue, exists := actual[key]
+		if !exists || !comparator.equalValues(expectedValue, actualValue) {
+			return false
+		}
+	}
+	return true
+}
+
+func (comparator *Comparator) equalSlices(expected []any, actual []any) bool {
+	if !comparator.enter("", nil) {
+		return false
+	}
+	defer comparator.leave()
+
+	if !comparator.checkArrayLength("", len(expected), len(actual)) || len(expected) != len(actual) {
+		return false
+	}
+
+	for i, expectedValue := range expected {
+		if !comparator.equalValues(expectedValue, actual[i]) {
+			return false
+		}
+	}
+	return true
+}
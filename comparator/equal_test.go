@@ -0,0 +1,67 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	testCases := []struct {
+		expected string
+		actual   string
+		equal    bool
+	}{
+		{"{\"name\": \"Bruce\", \"age\": 37, \"active\": true, \"alias\": null}",
+			"{\"active\": true, \"alias\": null, \"age\": 37, \"name\": \"Bruce\"}", true},
+		{"{\"name\": \"Bruce\"}", "{\"name\": \"Dick\"}", false},
+		{"{\"name\": \"Bruce\"}", "{\"name\": \"Bruce\", \"age\": 37}", false},
+		{"{\"name\": \"Bruce\", \"age\": 37}", "{\"name\": \"Bruce\", \"height\": 1.88}", false},
+		{"{\"id\": \"@ignore@\", \"tags\": [\"@ignore@\", 2]}", "{\"id\": {\"a\": 1}, \"tags\": [[1], 2]}", true},
+		{"{\"id\": \"@ignore@\"}", "{}", false},
+		{"[1, [2, 3]]", "[1, [2, 3]]", true},
+		{"[1, [2, 3]]", "[1, [3, 2]]", false},
+		{"[1, 2]", "[1, 2, 3]", false},
+		{"{\"a\": 1}", "{\"a\": \"1\"}", false},
+		{"{\"a\": {}}", "{\"a\": []}", false},
+		{"\"@ignore@\"", "[1]", true},
+		{"{\"a\": 1}", "{\"a\": ", false},
+	}
+
+	for _, testCase := range testCases {
+		if Equal([]byte(testCase.expected), []byte(testCase.actual)) != testCase.equal {
+			t.Errorf("wrong result for [%s] and [%s]", testCase.expected, testCase.actual)
+		}
+
+		// Equal must have the same result as Compare
+		_, err := NewComparator().Build().Compare([]byte(testCase.expected), []byte(testCase.actual))
+		if (err == nil) != testCase.equal {
+			t.Errorf("Compare has a different result for [%s] and [%s]: %v", testCase.expected, testCase.actual, err)
+		}
+	}
+}
+
+func TestEqualNotStrict(t *testing.T) {
+	comparator := NewComparator().StrictObjectCheck(false).Build()
+
+	if !comparator.Equal([]byte("{\"name\": \"Bruce\"}"), []byte("{\"name\": \"Bruce\", \"age\": 37}")) {
+		t.Error("extra fields must be allowed")
+	}
+	if comparator.Equal([]byte("{\"name\": \"Bruce\", \"age\": 37}"), []byte("{\"name\": \"Bruce\", \"height\": 1.88}")) {
+		t.Error("missing fields must not be allowed")
+	}
+}
+
+func TestEqualLimits(t *testing.T) {
+	nested := []byte(strings.Repeat("[", 100) + strings.Repeat("]", 100))
+	if !Equal(nested, nested) {
+		t.Error("nested arrays must be equal without limits")
+	}
+	if NewComparator().MaxDepth(10).Build().Equal(nested, nested) {
+		t.Error("JSONs deeper than the maximum depth must not be equal")
+	}
+
+	long := []byte("{\"items\": [1, 2, 3]}")
+	if NewComparator().MaxArrayLength(2).Build().Equal(long, long) {
+		t.Error("arrays longer than the maximum length must not be equal")
+	}
+}
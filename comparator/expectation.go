@@ -0,0 +1,45 @@
+package comparator
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-json/jsonpath"
+)
+
+// Expectation is an expected JSON that was parsed once by [Comparator.Compile], so that it can be matched
+// with many actual JSONs, for example in load or contract tests.
+//
+// An Expectation is immutable and goroutine safe.
+type Expectation struct {
+	comparator *Comparator
+	expected   any
+}
+
+// Compile parses the expected JSON and returns an [Expectation] that uses the options of the comparator.
+//
+// The recorder of the comparator is not used by the Expectation, since the output of concurrent matches
+// would be mixed up. To get the recorder output of a failed match, compare the same JSONs with [Comparator.Compare].
+func (comparator *Comparator) Compile(expected []byte) (*Expectation, error) {
+	if comparator.err != nil {
+		return nil, comparator.err
+	}
+
+	expectedJsonObject, err := unmarshalJson(expected)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Expectation{comparator.withoutRecorder(), expectedJsonObject}, nil
+}
+
+// Match compares the actual JSON with the expectation and returns the same errors as [Comparator.Compare].
+func (expectation *Expectation) Match(actual []byte) error {
+	actualJsonObject, err := unmarshalJson(actual)
+	if err != nil {
+		return err
+	}
+
+	comparison := expectation.comparator.begin(context.Background())
+	compareErrors := comparison.compareRoot(jsonpath.RootPath, jsonpath.RootPointer, "",
+		expectation.comparator.ignorePaths(expectation.expected, actualJsonObject), actualJsonObject, nil)
+	return comparison.result(compareErrors)
+}
@@ -0,0 +1,79 @@
+package comparator
+
+import (
+	"fmt"
+	"github.com/go-clarum/clarum-json/recorder"
+	"sync"
+	"testing"
+)
+
+func TestExpectationMatch(t *testing.T) {
+	comparator := NewComparator().Build()
+	expectation, err := comparator.Compile([]byte("{\"id\": \"@ignore@\", \"name\": \"Bruce\", \"gadgets\": [1, 2]}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkError(t, expectation.Match([]byte("{\"id\": 7, \"name\": \"Bruce\", \"gadgets\": [1, 2]}")), []string{})
+	checkError(t, expectation.Match([]byte("{\"id\": 8, \"name\": \"Bruce\", \"gadgets\": [1, 2]}")), []string{})
+	checkError(t, expectation.Match([]byte("{\"id\": 9, \"name\": \"Dick\", \"gadgets\": [1]}")), []string{
+		"[$.name] - value mismatch - expected [Bruce] but received [Dick]",
+		"[$.gadgets] - array size mismatch - expected [2] but received [1]",
+	})
+}
+
+func TestExpectationUsesComparatorOptions(t *testing.T) {
+	expected := []byte("{\"name\": \"Bruce\"}")
+	actual := []byte("{\"name\": \"Bruce\", \"age\": 37}")
+
+	strict, _ := NewComparator().Build().Compile(expected)
+	checkError(t, strict.Match(actual), []string{"[$.age] - unexpected field"})
+
+	notStrict, _ := NewComparator().StrictObjectCheck(false).Build().Compile(expected)
+	checkError(t, notStrict.Match(actual), []string{})
+}
+
+func TestExpectationDoesNotRecord(t *testing.T) {
+	defaultRecorder := recorder.NewDefaultRecorder()
+	expectation, _ := NewComparator().Recorder(defaultRecorder).Build().Compile([]byte("{\"name\": \"Bruce\"}"))
+
+	_ = expectation.Match([]byte("{\"name\": \"Dick\"}"))
+
+	if defaultRecorder.GetLog() != "" {
+		t.Errorf("the recorder must not be used: %s", defaultRecorder.GetLog())
+	}
+}
+
+func TestExpectationInvalidJson(t *testing.T) {
+	_, err := NewComparator().Build().Compile([]byte("{\"name\": "))
+	checkError(t, err, []string{"unable to parse JSON"})
+
+	expectation, _ := NewComparator().Build().Compile([]byte("{}"))
+	checkError(t, expectation.Match([]byte("{\"name\": ")), []string{"unable to parse JSON"})
+}
+
+func TestExpectationConcurrentMatches(t *testing.T) {
+	comparator := NewComparator().Build()
+	expectation, _ := comparator.Compile([]byte("{\"id\": \"@ignore@\", \"items\": [{\"name\": \"item\"}]}"))
+
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+
+			name := "item"
+			if i%2 == 1 {
+				name = fmt.Sprintf("item %d", i)
+			}
+			err := expectation.Match([]byte(fmt.Sprintf("{\"id\": %d, \"items\": [{\"name\": \"%s\"}]}", i, name)))
+
+			if i%2 == 0 && err != nil {
+				t.Errorf("match %d must succeed: %s", i, err)
+			} else if i%2 == 1 && len(Mismatches(err)) != 1 {
+				t.Errorf("match %d must fail with one error: %v", i, err)
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+}
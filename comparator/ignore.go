@@ -0,0 +1,105 @@
+package comparator
+
+import (
+	"errors"
+	"github.com/go-clarum/clarum-json/internal/matcher"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"strconv"
+	"strings"
+)
+
+var errIgnoreNotSupported = errors.New("paths to ignore are only supported when comparing entire documents")
+
+var pointerTokenUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// ignorePaths returns the expected JSON without the values selected by the paths to ignore. Values that exist
+// in the actual JSON are replaced by the '@ignore@' marker in the expected JSON and fields that only exist in the
+// expected JSON are removed from it, so neither a different value nor a missing or unexpected field is reported.
+// The expected JSON is copied first, as it may be reused by an [Expectation].
+func (comparator *Comparator) ignorePaths(expected any, actual any) any {
+	if len(comparator.pathsToIgnore) == 0 {
+		return expected
+	}
+
+	expected = copyJson(expected)
+	for _, path := range comparator.pathsToIgnore {
+		for _, node := range path.Select(actual) {
+			expected = replaceAt(expected, node.Pointer, func(any, bool) (any, bool) {
+				return matcher.Ignore, true
+			})
+		}
+
+		for _, node := range path.Select(expected) {
+			expected = replaceAt(expected, node.Pointer, func(value any, isField bool) (any, bool) {
+				if matcher.IsIgnore(value) || !isField {
+					return matcher.Ignore, true
+				}
+				return nil, false
+			})
+		}
+	}
+	return expected
+}
+
+// replaceAt replaces the value at the JSON Pointer with the one returned by the function, which receives the
+// current value and if it is an object field. If the function returns false, the field is removed instead.
+// Array items are only replaced if they exist.
+func replaceAt(document any, pointer string, replace func(value any, isField bool) (any, bool)) any {
+	if pointer == jsonpath.RootPointer {
+		value, _ := replace(document, false)
+		return value
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	parent := document
+	for _, token := range tokens[:len(tokens)-1] {
+		parent = child(parent, pointerTokenUnescaper.Replace(token))
+	}
+
+	last := pointerTokenUnescaper.Replace(tokens[len(tokens)-1])
+	switch parentValue := parent.(type) {
+	case map[string]any:
+		if value, keep := replace(parentValue[last], true); keep {
+			parentValue[last] = value
+		} else {
+			delete(parentValue, last)
+		}
+	case []any:
+		if index, err := strconv.Atoi(last); err == nil && index < len(parentValue) {
+			parentValue[index], _ = replace(parentValue[index], false)
+		}
+	}
+	return document
+}
+
+func child(value any, token string) any {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		return typedValue[token]
+	case []any:
+		if index, err := strconv.Atoi(token); err == nil && index < len(typedValue) {
+			return typedValue[index]
+		}
+	}
+	return nil
+}
+
+// copyJson copies the objects & arrays of a decoded JSON; the other values are immutable.
+func copyJson(value any) any {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(typedValue))
+		for key, fieldValue := range typedValue {
+			copied[key] = copyJson(fieldValue)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(typedValue))
+		for i, item := range typedValue {
+			copied[i] = copyJson(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
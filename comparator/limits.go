@@ -0,0 +1,176 @@
+package comparator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-json/internal"
+)
+
+var (
+	// ErrMaxDepthExceeded is wrapped by the [LimitError] returned when the JSON is nested deeper than [Builder.MaxDepth].
+	ErrMaxDepthExceeded = errors.New("maximum depth exceeded")
+	// ErrMaxArrayLengthExceeded is wrapped by the [LimitError] returned when an array is longer than
+	// [Builder.MaxArrayLength].
+	ErrMaxArrayLengthExceeded = errors.New("maximum array length exceeded")
+	// ErrMaxErrorsExceeded is wrapped by the [LimitError] returned when more mismatches are found than
+	// [Builder.MaxErrors].
+	ErrMaxErrorsExceeded = errors.New("maximum number of errors exceeded")
+)
+
+// LimitError is returned when a comparison is stopped because a limit of the [Comparator] was exceeded.
+// Use errors.Is with ErrMaxDepthExceeded, ErrMaxArrayLengthExceeded or ErrMaxErrorsExceeded to find out which one.
+//
+// The Path is the location of the value that exceeded the limit; for ErrMaxErrorsExceeded it is the path
+// of the first mismatch that is not returned. The error is joined with the mismatches found before the comparison
+// was stopped.
+type LimitError struct {
+	Err   error
+	Path  string
+	Limit int
+}
+
+func (limitError *LimitError) Error() string {
+	return fmt.Sprintf("[%s] - comparison stopped - %s - limit is [%d]", limitError.Path, limitError.Err,
+		limitError.Limit)
+}
+
+func (limitError *LimitError) Unwrap() error {
+	return limitError.Err
+}
+
+// TruncatedError is returned together with the mismatches found when the comparison was stopped early,
+// as configured with [Builder.StopAfter] or [Builder.FailFast].
+// Skipped is the number of values of the expected JSON that were not checked, including the values of the skipped
+// objects & arrays.
+type TruncatedError struct {
+	Mismatches int
+	Skipped    int
+}
+
+func (truncatedError *TruncatedError) Error() string {
+	return fmt.Sprintf("comparison stopped after [%d] mismatch(es) - [%d] check(s) skipped",
+		truncatedError.Mismatches, truncatedError.Skipped)
+}
+
+// traversal is the state of a single comparison, which is needed to stop it when the context is done,
+// a limit is exceeded or enough mismatches were found.
+type traversal struct {
+	ctx   context.Context
+	depth int
+	// err is the reason why the comparison was stopped
+	err     error
+	skipped int
+}
+
+// begin returns a copy of the comparator for a single comparison.
+func (comparator *Comparator) begin(ctx context.Context) *Comparator {
+	return &Comparator{options: comparator.options, traversal: &traversal{ctx: ctx}}
+}
+
+// enter is called when the comparison enters an object or an array.
+// It returns false if the comparison must stop.
+func (comparator *Comparator) enter(path string, compareErrors []error) bool {
+	if comparator.stopped(compareErrors) {
+		return false
+	}
+
+	if maxDepth := comparator.maxDepth; maxDepth > 0 && comparator.traversal.depth >= maxDepth {
+		comparator.traversal.err = &LimitError{ErrMaxDepthExceeded, path, maxDepth}
+		return false
+	}
+
+	comparator.traversal.depth++
+	return true
+}
+
+func (comparator *Comparator) leave() {
+	comparator.traversal.depth--
+}
+
+// checkArrayLength stops the comparison if one of the arrays is longer than allowed.
+func (comparator *Comparator) checkArrayLength(path string, expectedLen int, actualLen int) bool {
+	maxArrayLength := comparator.maxArrayLength
+	if maxArrayLength > 0 && (expectedLen > maxArrayLength || actualLen > maxArrayLength) {
+		comparator.traversal.err = &LimitError{ErrMaxArrayLengthExceeded, path, maxArrayLength}
+		return false
+	}
+	return true
+}
+
+// stopped checks if the comparison must stop, because the context is done or enough errors were found.
+func (comparator *Comparator) stopped(compareErrors []error) bool {
+	if comparator.traversal.err == nil {
+		if err := comparator.traversal.ctx.Err(); err != nil {
+			comparator.traversal.err = err
+		} else if stopAfter := comparator.stopAfter; stopAfter > 0 && len(compareErrors) >= stopAfter {
+			comparator.traversal.err = &TruncatedError{Mismatches: stopAfter}
+		} else {
+			comparator.checkErrorCount(compareErrors)
+		}
+	}
+
+	return comparator.traversal.err != nil
+}
+
+// skip counts the values of the expected JSON that are not checked because the comparison was stopped.
+func (comparator *Comparator) skip(expected any) {
+	comparator.traversal.skipped += countValues(expected)
+}
+
+func countValues(value any) int {
+	count := 1
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for _, child := range typedValue {
+			count += countValues(child)
+		}
+	case []any:
+		for _, child := range typedValue {
+			count += countValues(child)
+		}
+	}
+	return count
+}
+
+func (comparator *Comparator) checkErrorCount(compareErrors []error) {
+	if maxErrors := comparator.maxErrors; maxErrors > 0 && len(compareErrors) > maxErrors {
+		var path string
+		if mismatch, isMismatch := compareErrors[maxErrors].(*Mismatch); isMismatch {
+			path = mismatch.Path
+		}
+		comparator.traversal.err = &LimitError{ErrMaxErrorsExceeded, path, maxErrors}
+	}
+}
+
+// result joins the mismatches found with the error that stopped the comparison, if there is one.
+// It must be called before the log of the recorder is read, since a truncated comparison is recorded.
+func (comparator *Comparator) result(compareErrors []error) error {
+	// the mismatches found after the last check of the traversal are counted here
+	if comparator.traversal.err == nil {
+		if stopAfter := comparator.stopAfter; stopAfter > 0 && len(compareErrors) > stopAfter {
+			comparator.traversal.err = &TruncatedError{Mismatches: stopAfter}
+		} else {
+			comparator.checkErrorCount(compareErrors)
+		}
+	}
+
+	if truncatedError, isTruncated := comparator.traversal.err.(*TruncatedError); isTruncated {
+		// mismatches found in the same step as the last reported one are not reported either
+		dropped := max(len(compareErrors)-truncatedError.Mismatches, 0)
+		truncatedError.Skipped = comparator.traversal.skipped + dropped
+		if truncatedError.Skipped == 0 {
+			return errors.Join(compareErrors...)
+		}
+
+		internal.RecordTruncation(comparator.recorder, truncatedError.Error())
+		compareErrors = compareErrors[:len(compareErrors)-dropped]
+	} else if comparator.traversal.err == nil {
+		return errors.Join(compareErrors...)
+	}
+
+	if maxErrors := comparator.maxErrors; maxErrors > 0 && len(compareErrors) > maxErrors {
+		compareErrors = compareErrors[:maxErrors]
+	}
+	return errors.Join(append(compareErrors, comparator.traversal.err)...)
+}
@@ -0,0 +1,186 @@
+package comparator
+
+import (
+	"context"
+	"errors"
+	"github.com/go-clarum/clarum-json/recorder"
+	"strings"
+	"testing"
+)
+
+func TestMaxDepth(t *testing.T) {
+	comparator := NewComparator().MaxDepth(3).Build()
+
+	_, err := comparator.Compare([]byte("{\"a\": [{\"b\": 1}]}"), []byte("{\"a\": [{\"b\": 1}]}"))
+	checkError(t, err, []string{})
+
+	_, err = comparator.Compare([]byte("{\"a\": [{\"b\": [1]}]}"), []byte("{\"a\": [{\"b\": [1]}]}"))
+	checkError(t, err, []string{"[$.a[0].b] - comparison stopped - maximum depth exceeded - limit is [3]"})
+	checkLimitError(t, err, ErrMaxDepthExceeded, "$.a[0].b")
+}
+
+func TestMaxArrayLength(t *testing.T) {
+	comparator := NewComparator().MaxArrayLength(2).Build()
+
+	_, err := comparator.Compare([]byte("{\"a\": [1, 2]}"), []byte("{\"a\": [1, 2]}"))
+	checkError(t, err, []string{})
+
+	_, err = comparator.Compare([]byte("{\"a\": [1, 2]}"), []byte("{\"a\": [1, 2, 3]}"))
+	checkError(t, err, []string{"[$.a] - comparison stopped - maximum array length exceeded - limit is [2]"})
+	checkLimitError(t, err, ErrMaxArrayLengthExceeded, "$.a")
+}
+
+func TestMaxErrors(t *testing.T) {
+	comparator := NewComparator().MaxErrors(2).Build()
+
+	_, err := comparator.Compare([]byte("[1, 2, 3, 4]"), []byte("[1, 5, 6, 4]"))
+	if errors.Is(err, ErrMaxErrorsExceeded) || len(Mismatches(err)) != 2 {
+		t.Errorf("expected 2 mismatches without a limit error but found %v", err)
+	}
+
+	_, err = comparator.Compare([]byte("[1, 2, 3, [4, 5]]"), []byte("[7, 8, 9, [10, 11]]"))
+	checkError(t, err, []string{
+		"[$[0]] - value mismatch - expected [1] but received [7]",
+		"[$[1]] - value mismatch - expected [2] but received [8]",
+		"[$[2]] - comparison stopped - maximum number of errors exceeded - limit is [2]",
+	})
+	checkLimitError(t, err, ErrMaxErrorsExceeded, "$[2]")
+
+	if mismatches := Mismatches(err); len(mismatches) != 2 {
+		t.Errorf("expected 2 mismatches but found %d", len(mismatches))
+	}
+}
+
+func TestMaxErrorsCountsLastMismatches(t *testing.T) {
+	comparator := NewComparator().MaxErrors(1).Build()
+
+	_, err := comparator.Compare([]byte("{\"a\": 1}"), []byte("{\"a\": 1, \"b\": 2}"))
+
+	checkLimitError(t, err, ErrMaxErrorsExceeded, "$.b")
+	if mismatches := Mismatches(err); len(mismatches) != 1 || mismatches[0].Kind != FieldCountMismatch {
+		t.Errorf("expected only the field count mismatch but found %v", mismatches)
+	}
+}
+
+func TestCompareContextCancelled(t *testing.T) {
+	comparator := NewComparator().Build()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := comparator.CompareContext(ctx, []byte("{\"a\": 1}"), []byte("{\"a\": 2}"))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the comparison to be cancelled but found %v", err)
+	}
+	if len(Mismatches(err)) != 0 {
+		t.Errorf("no mismatches expected after cancellation: %v", err)
+	}
+}
+
+func TestCompareContextDeadline(t *testing.T) {
+	comparator := NewComparator().Build()
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	document := []byte("[" + strings.Repeat("[1, 2], ", 100) + "[1, 2]]")
+	_, err := comparator.CompareContext(ctx, document, document)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be exceeded but found %v", err)
+	}
+}
+
+func TestLimitsApplyToEachComparison(t *testing.T) {
+	comparator := NewComparator().MaxDepth(1).MaxErrors(1).Build()
+
+	for i := 0; i < 3; i++ {
+		_, err := comparator.Compare([]byte("{\"a\": 1}"), []byte("{\"a\": 2}"))
+		checkError(t, err, []string{"[$.a] - value mismatch - expected [1] but received [2]"})
+
+		var limitError *LimitError
+		if errors.As(err, &limitError) {
+			t.Errorf("no limit expected: %s", limitError)
+		}
+	}
+}
+
+func TestMaxErrorsWithOtherErrors(t *testing.T) {
+	comparison := NewComparator().MaxErrors(1).Build().begin(context.Background())
+
+	comparison.checkErrorCount([]error{&Mismatch{Path: "$.a"}, context.Canceled})
+
+	checkLimitError(t, comparison.traversal.err, ErrMaxErrorsExceeded, "")
+}
+
+func checkLimitError(t *testing.T, err error, expectedErr error, expectedPath string) {
+	var limitError *LimitError
+	if !errors.As(err, &limitError) {
+		t.Errorf("expected a LimitError but found %v", err)
+		return
+	}
+
+	if !errors.Is(err, expectedErr) || limitError.Path != expectedPath {
+		t.Errorf("wrong limit error: %s", limitError)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	comparator := NewComparator().FailFast().Recorder(recorder.NewDefaultRecorder()).Build()
+
+	recorderLog, err := comparator.Compare([]byte("[1, 2, 3, [4, 5]]"), []byte("[7, 8, 9, [10, 11]]"))
+
+	checkError(t, err, []string{
+		"[$[0]] - value mismatch - expected [1] but received [7]",
+		"comparison stopped after [1] mismatch(es) - [5] check(s) skipped",
+	})
+	checkTruncatedError(t, err, 1, 5)
+	checkRecorderLog(t, "[\n  7, <-- value mismatch - expected [1]\n]\n"+
+		"... <-- comparison stopped after [1] mismatch(es) - [5] check(s) skipped\n", recorderLog)
+}
+
+func TestStopAfter(t *testing.T) {
+	comparator := NewComparator().StopAfter(2).Build()
+
+	_, err := comparator.Compare([]byte("{\"a\": [1, 2, 3]}"), []byte("{\"a\": [4, 5, 6]}"))
+	checkTruncatedError(t, err, 2, 1)
+
+	// the mismatches of unexpected fields are found together
+	_, err = comparator.Compare([]byte("{\"a\": 1}"), []byte("{\"a\": 1, \"b\": 2, \"c\": 3}"))
+	checkTruncatedError(t, err, 2, 1)
+}
+
+func TestStopAfterWithoutSkippedChecks(t *testing.T) {
+	comparator := NewComparator().FailFast().Build()
+
+	_, err := comparator.Compare([]byte("[1, 2]"), []byte("[1, 3]"))
+
+	checkError(t, err, []string{"[$[1]] - value mismatch - expected [2] but received [3]"})
+	var truncatedError *TruncatedError
+	if errors.As(err, &truncatedError) {
+		t.Errorf("the comparison was not truncated: %s", truncatedError)
+	}
+}
+
+func TestFailFastSideBySideRecorder(t *testing.T) {
+	comparator := NewComparator().FailFast().Recorder(recorder.NewSideBySideRecorder()).Build()
+
+	recorderLog, _ := comparator.Compare([]byte("[1, 2]"), []byte("[3, 4]"))
+
+	checkRecorderLog(t, "[      [\n"+
+		"  1, |   3,\n"+
+		"]      ]\n"+
+		"... <-- comparison stopped after [1] mismatch(es) - [1] check(s) skipped\n", recorderLog)
+}
+
+func checkTruncatedError(t *testing.T, err error, expectedMismatches int, expectedSkipped int) {
+	var truncatedError *TruncatedError
+	if !errors.As(err, &truncatedError) {
+		t.Errorf("expected a TruncatedError but found %v", err)
+		return
+	}
+
+	if truncatedError.Mismatches != expectedMismatches || truncatedError.Skipped != expectedSkipped ||
+		len(Mismatches(err)) != expectedMismatches {
+		t.Errorf("wrong truncation: %s", err)
+	}
+}
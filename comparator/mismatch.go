@@ -0,0 +1,61 @@
+package comparator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MismatchKind describes how the actual JSON does not match the expected one.
+type MismatchKind string
+
+const (
+	ValueMismatch      MismatchKind = "value mismatch"
+	TypeMismatch       MismatchKind = "type mismatch"
+	MissingField       MismatchKind = "missing field"
+	UnexpectedField    MismatchKind = "unexpected field"
+	ArraySizeMismatch  MismatchKind = "array size mismatch"
+	FieldCountMismatch MismatchKind = "field count mismatch"
+)
+
+// Mismatch is the error created by the [Comparator] for every difference it finds.
+// The Compare method returns all of them joined into one error, use [Mismatches] to get them back.
+//
+// The location of the mismatch is given both as a JSON path and as a JSON Pointer (RFC 6901).
+// Expected & Actual contain the values found at that location. The Expected value is nil for unexpected fields
+// and the Actual value is nil for missing fields. For field count mismatches, they contain the number of fields.
+type Mismatch struct {
+	Kind     MismatchKind
+	Path     string
+	Pointer  string
+	Expected any
+	Actual   any
+	message  string
+}
+
+// NewMismatch creates a mismatch for validations that report their findings like the [Comparator], for example
+// the schema package. The message is prefixed with the path, like all messages of the Comparator.
+func NewMismatch(kind MismatchKind, path string, pointer string, expected any, actual any, message string) *Mismatch {
+	return &Mismatch{kind, path, pointer, expected, actual, fmt.Sprintf("[%s] - %s", path, message)}
+}
+
+func (mismatch *Mismatch) Error() string {
+	return mismatch.message
+}
+
+// Mismatches returns all the [Mismatch] errors contained in the error returned by the [Comparator].
+func Mismatches(err error) []*Mismatch {
+	var result []*Mismatch
+
+	if joinedErrors, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, joinedError := range joinedErrors.Unwrap() {
+			result = append(result, Mismatches(joinedError)...)
+		}
+	} else {
+		var mismatch *Mismatch
+		if errors.As(err, &mismatch) {
+			result = append(result, mismatch)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,80 @@
+package comparator
+
+import "encoding/json"
+
+const (
+	patchAdd     = "add"
+	patchRemove  = "remove"
+	patchReplace = "replace"
+)
+
+// PatchOperation is a single operation of a JSON Patch (RFC 6902). The Path is a JSON Pointer (RFC 6901).
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON always writes the value of the operations that need one, even if it is null,
+// and never writes it for the operations that do not have a value.
+func (operation PatchOperation) MarshalJSON() ([]byte, error) {
+	switch operation.Op {
+	case patchAdd, patchReplace, "test":
+		type withValue PatchOperation
+		return json.Marshal(withValue(operation))
+	default:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{operation.Op, operation.Path})
+	}
+}
+
+// Patch is a JSON Patch document (RFC 6902). It can be serialized with json.Marshal.
+type Patch []PatchOperation
+
+// NewPatch creates the JSON Patch that transforms the actual JSON into the expected one,
+// from the error returned by the [Comparator].
+//
+// The patch only contains the differences the comparator validated: ignored fields are left untouched
+// and unexpected fields are only removed if the comparator does a strict object check.
+// Arrays with a different size are replaced as a whole.
+func NewPatch(err error) Patch {
+	return createPatch(err, false)
+}
+
+// NewReversePatch creates the JSON Patch that transforms the expected JSON into the actual one,
+// from the error returned by the [Comparator]. This can be used to update an expected JSON that is out of date,
+// while keeping its '@ignore@' markers.
+//
+// Arrays with a different size are replaced as a whole, including any '@ignore@' marker they contain.
+func NewReversePatch(err error) Patch {
+	return createPatch(err, true)
+}
+
+// createPatch converts each mismatch into an operation that changes the actual value into the expected one,
+// or the other way around if reversed. A missing field has to be added when patching towards the expected JSON,
+// but removed when patching towards the actual one.
+func createPatch(err error, reverse bool) Patch {
+	patch := Patch{}
+
+	for _, mismatch := range Mismatches(err) {
+		target := mismatch.Expected
+		if reverse {
+			target = mismatch.Actual
+		}
+
+		switch mismatch.Kind {
+		case ValueMismatch, TypeMismatch, ArraySizeMismatch:
+			patch = append(patch, PatchOperation{patchReplace, mismatch.Pointer, target})
+		case MissingField, UnexpectedField:
+			if (mismatch.Kind == MissingField) != reverse {
+				patch = append(patch, PatchOperation{patchAdd, mismatch.Pointer, target})
+			} else {
+				patch = append(patch, PatchOperation{Op: patchRemove, Path: mismatch.Pointer})
+			}
+		}
+	}
+
+	return patch
+}
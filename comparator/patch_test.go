@@ -0,0 +1,156 @@
+package comparator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPatchMatch(t *testing.T) {
+	expectedValue := []byte("{\"active\": true}")
+	actualValue := []byte("{\"active\": true}")
+
+	_, err := NewComparator().Build().Compare(expectedValue, actualValue)
+
+	if patch := NewPatch(err); len(patch) != 0 {
+		t.Errorf("patch must be empty: %v", patch)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	expectedValue := []byte("{" +
+		"\"name\": \"Bruce\"," +
+		"\"age\": 37," +
+		"\"location\": {" +
+		"\"street\": \"Mountain Drive\"," +
+		"\"timestamp\": \"@ignore@\"" +
+		"}," +
+		"\"aliases\": [\"Batman\"]," +
+		"\"height\": \"1.879\"" +
+		"}")
+	actualValue := []byte("{" +
+		"\"name\": \"Bruce\"," +
+		"\"age\": 38," +
+		"\"location\": {" +
+		"\"address\": \"Mountain Drive\"," +
+		"\"timestamp\": \"2024-01-03 23:42:00\"" +
+		"}," +
+		"\"aliases\": [\"Batman\", \"Robin\"]," +
+		"\"height\": 1.879" +
+		"}")
+
+	_, err := NewComparator().Build().Compare(expectedValue, actualValue)
+
+	checkPatch(t, NewPatch(err), []string{
+		"{\"op\":\"replace\",\"path\":\"/age\",\"value\":37}",
+		"{\"op\":\"add\",\"path\":\"/location/street\",\"value\":\"Mountain Drive\"}",
+		"{\"op\":\"remove\",\"path\":\"/location/address\"}",
+		"{\"op\":\"replace\",\"path\":\"/aliases\",\"value\":[\"Batman\"]}",
+		"{\"op\":\"replace\",\"path\":\"/height\",\"value\":\"1.879\"}",
+	})
+
+	checkPatch(t, NewReversePatch(err), []string{
+		"{\"op\":\"replace\",\"path\":\"/age\",\"value\":38}",
+		"{\"op\":\"remove\",\"path\":\"/location/street\"}",
+		"{\"op\":\"add\",\"path\":\"/location/address\",\"value\":\"Mountain Drive\"}",
+		"{\"op\":\"replace\",\"path\":\"/aliases\",\"value\":[\"Batman\",\"Robin\"]}",
+		"{\"op\":\"replace\",\"path\":\"/height\",\"value\":1.879}",
+	})
+}
+
+func TestPatchArrayItemsAndEscaping(t *testing.T) {
+	expectedValue := []byte("[{\"a/b\": 1, \"m~n\": [true]}]")
+	actualValue := []byte("[{\"a/b\": 2, \"m~n\": [false]}]")
+
+	_, err := NewComparator().Build().Compare(expectedValue, actualValue)
+
+	checkPatch(t, NewPatch(err), []string{
+		"{\"op\":\"replace\",\"path\":\"/0/a~1b\",\"value\":1}",
+		"{\"op\":\"replace\",\"path\":\"/0/m~0n/0\",\"value\":true}",
+	})
+}
+
+func TestPatchRootMismatch(t *testing.T) {
+	_, err := NewComparator().Build().Compare([]byte("{}"), []byte("[]"))
+
+	checkPatch(t, NewPatch(err), []string{
+		"{\"op\":\"replace\",\"path\":\"\",\"value\":{}}",
+	})
+}
+
+func TestPatchNullValues(t *testing.T) {
+	expectedValue := []byte("{\"a\": null, \"b\": [null]}")
+	actualValue := []byte("{\"a\": 1, \"b\": [], \"c\": null}")
+
+	_, err := NewComparator().Build().Compare(expectedValue, actualValue)
+
+	checkPatch(t, NewPatch(err), []string{
+		"{\"op\":\"replace\",\"path\":\"/a\",\"value\":null}",
+		"{\"op\":\"replace\",\"path\":\"/b\",\"value\":[null]}",
+		"{\"op\":\"remove\",\"path\":\"/c\"}",
+	})
+
+	reversePatch := NewReversePatch(err)
+	checkPatch(t, reversePatch, []string{
+		"{\"op\":\"replace\",\"path\":\"/a\",\"value\":1}",
+		"{\"op\":\"replace\",\"path\":\"/b\",\"value\":[]}",
+		"{\"op\":\"add\",\"path\":\"/c\",\"value\":null}",
+	})
+
+	serialized, _ := json.Marshal(reversePatch)
+	var operations []map[string]any
+	if err := json.Unmarshal(serialized, &operations); err != nil {
+		t.Fatal(err)
+	}
+	for _, operation := range operations {
+		if _, hasValue := operation["value"]; hasValue == (operation["op"] == "remove") {
+			t.Errorf("wrong value in the operation after a round trip: %v", operation)
+		}
+	}
+}
+
+func TestMismatches(t *testing.T) {
+	expectedValue := []byte("{\"name\": \"Bruce\", \"age\": 37}")
+	actualValue := []byte("{\"age\": 38}")
+
+	_, err := NewComparator().Build().Compare(expectedValue, actualValue)
+	mismatches := Mismatches(err)
+
+	if len(mismatches) != 3 {
+		t.Fatalf("expected 3 mismatches but found %d", len(mismatches))
+	}
+	for _, mismatch := range mismatches {
+		switch mismatch.Kind {
+		case FieldCountMismatch:
+			if mismatch.Path != "$" || mismatch.Pointer != "" || mismatch.Expected != 2 || mismatch.Actual != 1 {
+				t.Errorf("wrong mismatch: %+v", mismatch)
+			}
+		case MissingField:
+			if mismatch.Path != "$.name" || mismatch.Pointer != "/name" || mismatch.Expected != "Bruce" || mismatch.Actual != nil {
+				t.Errorf("wrong mismatch: %+v", mismatch)
+			}
+		case ValueMismatch:
+			if mismatch.Path != "$.age" || mismatch.Pointer != "/age" || mismatch.Expected != 37.0 || mismatch.Actual != 38.0 {
+				t.Errorf("wrong mismatch: %+v", mismatch)
+			}
+		default:
+			t.Errorf("unexpected mismatch: %+v", mismatch)
+		}
+	}
+}
+
+func checkPatch(t *testing.T, patch Patch, expectedOperations []string) {
+	if len(patch) != len(expectedOperations) {
+		t.Errorf("expected %d operations but found %d: %v", len(expectedOperations), len(patch), patch)
+	}
+
+	operations := map[string]bool{}
+	for _, operation := range patch {
+		serialized, _ := json.Marshal(operation)
+		operations[string(serialized)] = true
+	}
+	for _, expectedOperation := range expectedOperations {
+		if !operations[expectedOperation] {
+			t.Errorf("missing operation: %s", expectedOperation)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package comparator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-json/recorder"
+	"time"
+)
+
+const (
+	DefaultPollTimeout  = 10 * time.Second
+	DefaultPollInterval = 100 * time.Millisecond
+)
+
+// ErrPollingExhausted is returned by [Comparator.CompareEventually] when the actual JSON did not match
+// within the timeout or the maximum number of attempts.
+var ErrPollingExhausted = errors.New("polling budget exhausted")
+
+// PollPolicy configures how [Comparator.CompareEventually] repeats the comparison.
+// The zero value polls every 100 milliseconds for up to 10 seconds.
+type PollPolicy struct {
+	// Timeout is the maximum duration of the polling. Default is [DefaultPollTimeout].
+	Timeout time.Duration
+	// MaxAttempts is the maximum number of times the actual JSON is fetched. Default is 0, which means there is no limit.
+	MaxAttempts int
+	// Interval is the delay between the first two attempts. Default is [DefaultPollInterval].
+	Interval time.Duration
+	// Backoff multiplies the delay after every attempt. Default is 1, which keeps the delay constant.
+	Backoff float64
+	// MaxInterval is the maximum delay between two attempts. Default is 0, which means there is no maximum.
+	MaxInterval time.Duration
+}
+
+// PollResult contains the statistics of the polling and the recorder log of the last comparison.
+type PollResult struct {
+	Log         string
+	Attempts    int
+	FetchErrors int
+	Elapsed     time.Duration
+}
+
+// CompareEventually fetches the actual JSON until it matches the expected one, for systems that only
+// converge after a while. An attempt whose fetch returns an error is counted and the polling continues.
+//
+// The intermediate attempts are checked like with [Comparator.Equal]. Only the last actual JSON is compared with
+// [Comparator.Compare], so the result contains the recorder log of that comparison only, if the recorder implements
+// [recorder.ResettableRecorder]. If the polling stops without a match, the error wraps [ErrPollingExhausted],
+// or the error of the context if it was cancelled, together with the mismatches of the last comparison or the last
+// fetch error. The limits of the comparator apply to every attempt: an actual JSON that exceeds one stops
+// the polling and the [LimitError] is returned as it is, joined with the mismatches found.
+func (comparator *Comparator) CompareEventually(ctx context.Context, expected []byte,
+	fetch func(ctx context.Context) ([]byte, error), policy PollPolicy) (PollResult, error) {
+	if comparator.err != nil {
+		return PollResult{}, comparator.err
+	}
+	expectedJsonObject, err := unmarshalJson(expected)
+	if err != nil {
+		return PollResult{}, err
+	}
+
+	start := time.Now()
+	policy = policy.withDefaults()
+	pollCtx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+
+	result := PollResult{}
+	interval := policy.Interval
+
+	var actual []byte
+	var fetchErr error
+	matched, exceeded := false, false
+	for !matched && !exceeded {
+		result.Attempts++
+		comparator.logger.Debug(fmt.Sprintf("json comparator - polling attempt %d", result.Attempts))
+
+		actual, fetchErr = fetch(pollCtx)
+		if fetchErr != nil {
+			result.FetchErrors++
+		} else {
+			var stopErr error
+			matched, stopErr = comparator.matches(pollCtx, expectedJsonObject, actual)
+			// Compare stops with the same error, so polling further does not help
+			var limitError *LimitError
+			exceeded = errors.As(stopErr, &limitError)
+		}
+
+		if !matched && !exceeded && (result.Attempts == policy.MaxAttempts || !sleep(pollCtx, interval)) {
+			break
+		}
+		interval = policy.nextInterval(interval)
+	}
+	result.Elapsed = time.Since(start)
+
+	stopReason := ErrPollingExhausted
+	if ctx.Err() != nil {
+		stopReason = ctx.Err()
+	}
+
+	if fetchErr != nil {
+		return result, fmt.Errorf("%w after [%d] attempt(s) in [%s] - last fetch failed: %w", stopReason,
+			result.Attempts, result.Elapsed.Round(time.Millisecond), fetchErr)
+	}
+
+	// the log must only contain the last comparison, not the ones done before with the same comparator
+	if resettableRecorder, isResettable := comparator.recorder.(recorder.ResettableRecorder); isResettable {
+		resettableRecorder.Reset()
+	}
+	result.Log, err = comparator.Compare(expected, actual)
+	if matched || exceeded {
+		return result, err
+	} else if err != nil {
+		err = fmt.Errorf("%w after [%d] attempt(s) in [%s]\n%w", stopReason,
+			result.Attempts, result.Elapsed.Round(time.Millisecond), err)
+	}
+	return result, err
+}
+
+func (comparator *Comparator) matches(ctx context.Context, expected any, actual []byte) (bool, error) {
+	var actualJsonObject any
+	if json.Unmarshal(actual, &actualJsonObject) != nil {
+		return false, nil
+	}
+
+	return comparator.equal(ctx, comparator.ignorePaths(expected, actualJsonObject), actualJsonObject)
+}
+
+func (policy PollPolicy) withDefaults() PollPolicy {
+	if policy.Timeout <= 0 {
+		policy.Timeout = DefaultPollTimeout
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = DefaultPollInterval
+	}
+	if policy.Backoff < 1 {
+		policy.Backoff = 1
+	}
+	return policy
+}
+
+func (policy PollPolicy) nextInterval(interval time.Duration) time.Duration {
+	next := time.Duration(float64(interval) * policy.Backoff)
+	if policy.MaxInterval > 0 && next > policy.MaxInterval {
+		return policy.MaxInterval
+	}
+	return next
+}
+
+// sleep waits for the given duration and returns false if the context is done before.
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
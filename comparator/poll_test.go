@@ -0,0 +1,168 @@
+package comparator
+
+import (
+	"context"
+	"errors"
+	"github.com/go-clarum/clarum-json/recorder"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompareEventually(t *testing.T) {
+	comparator := NewComparator().Recorder(recorder.NewDefaultRecorder()).Build()
+	fetch := fetchSequence(nil, "{\"status\": \"PENDING\"}", "{\"status\": \"PENDING\"}", "{\"status\": \"DONE\"}")
+
+	result, err := comparator.CompareEventually(context.Background(), []byte("{\"status\": \"DONE\"}"), fetch,
+		PollPolicy{Interval: time.Millisecond})
+
+	checkError(t, err, []string{})
+	if result.Attempts != 3 || result.FetchErrors != 0 {
+		t.Errorf("wrong statistics: %+v", result)
+	}
+	checkRecorderLog(t, "{\n  \"status\": DONE,\n}\n", result.Log)
+}
+
+func TestCompareEventuallyMaxAttempts(t *testing.T) {
+	comparator := NewComparator().Recorder(recorder.NewDefaultRecorder()).Build()
+	fetch := fetchSequence(nil, "{\"status\": \"PENDING\"}")
+
+	result, err := comparator.CompareEventually(context.Background(), []byte("{\"status\": \"DONE\"}"), fetch,
+		PollPolicy{Interval: time.Millisecond, MaxAttempts: 3})
+
+	if !errors.Is(err, ErrPollingExhausted) || result.Attempts != 3 {
+		t.Errorf("expected the polling to stop after 3 attempts but found %d: %v", result.Attempts, err)
+	}
+	if mismatches := Mismatches(err); len(mismatches) != 1 || mismatches[0].Path != "$.status" {
+		t.Errorf("expected the mismatch of the last comparison but found %v", mismatches)
+	}
+	checkRecorderLog(t, "{\n  \"status\": PENDING, <-- value mismatch - expected [DONE]\n}\n", result.Log)
+}
+
+func TestCompareEventuallyTimeout(t *testing.T) {
+	comparator := NewComparator().Build()
+	fetch := fetchSequence(nil, "{\"status\": \"PENDING\"}")
+
+	result, err := comparator.CompareEventually(context.Background(), []byte("{\"status\": \"DONE\"}"), fetch,
+		PollPolicy{Timeout: 20 * time.Millisecond, Interval: 5 * time.Millisecond})
+
+	if !errors.Is(err, ErrPollingExhausted) || result.Attempts < 2 {
+		t.Errorf("expected the polling to time out after multiple attempts but found %d: %v", result.Attempts, err)
+	}
+	if result.Elapsed < 20*time.Millisecond {
+		t.Errorf("the polling stopped before the timeout: %s", result.Elapsed)
+	}
+}
+
+func TestCompareEventuallyFetchErrors(t *testing.T) {
+	comparator := NewComparator().Build()
+	unavailable := errors.New("service unavailable")
+
+	fetch := fetchSequence(unavailable, "", "{\"status\": \"DONE\"}")
+	result, err := comparator.CompareEventually(context.Background(), []byte("{\"status\": \"DONE\"}"), fetch,
+		PollPolicy{Interval: time.Millisecond})
+
+	checkError(t, err, []string{})
+	if result.Attempts != 2 || result.FetchErrors != 1 {
+		t.Errorf("wrong statistics: %+v", result)
+	}
+
+	fetch = fetchSequence(unavailable, "")
+	result, err = comparator.CompareEventually(context.Background(), []byte("{\"status\": \"DONE\"}"), fetch,
+		PollPolicy{Interval: time.Millisecond, MaxAttempts: 2})
+
+	if !errors.Is(err, ErrPollingExhausted) || !errors.Is(err, unavailable) || result.FetchErrors != 2 {
+		t.Errorf("expected the last fetch error but found %v", err)
+	}
+}
+
+func TestCompareEventuallyCancelled(t *testing.T) {
+	comparator := NewComparator().Build()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	fetch := func(ctx context.Context) ([]byte, error) {
+		cancel()
+		return []byte("{\"status\": \"PENDING\"}"), nil
+	}
+	result, err := comparator.CompareEventually(ctx, []byte("{\"status\": \"DONE\"}"), fetch, PollPolicy{})
+
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrPollingExhausted) || result.Attempts != 1 {
+		t.Errorf("expected the polling to be cancelled but found %v", err)
+	}
+}
+
+func TestCompareEventuallyLogOfLastComparisonOnly(t *testing.T) {
+	comparator := NewComparator().Recorder(recorder.NewDefaultRecorder()).Build()
+	_, _ = comparator.Compare([]byte("{\"status\": \"NEW\"}"), []byte("{\"status\": \"NEW\"}"))
+
+	result, err := comparator.CompareEventually(context.Background(), []byte("{\"status\": \"DONE\"}"),
+		fetchSequence(nil, "{\"status\": \"DONE\"}"), PollPolicy{Interval: time.Millisecond})
+
+	checkError(t, err, []string{})
+	checkRecorderLog(t, "{\n  \"status\": DONE,\n}\n", result.Log)
+}
+
+func TestCompareEventuallyLimitAfterMatch(t *testing.T) {
+	comparator := NewComparator().MaxDepth(1).Build()
+
+	_, err := comparator.CompareEventually(context.Background(), []byte("{\"a\": {\"b\": 1}}"),
+		fetchSequence(nil, "{\"a\": {\"b\": 1}}"), PollPolicy{Interval: time.Millisecond})
+
+	checkLimitError(t, err, ErrMaxDepthExceeded, "$.a")
+	if errors.Is(err, ErrPollingExhausted) {
+		t.Errorf("a matching JSON must not exhaust the polling: %v", err)
+	}
+}
+
+func TestCompareEventuallyInvalidJson(t *testing.T) {
+	comparator := NewComparator().Build()
+
+	_, err := comparator.CompareEventually(context.Background(), []byte("{\"status\": "),
+		fetchSequence(nil, "{}"), PollPolicy{})
+	if err == nil || errors.Is(err, ErrPollingExhausted) {
+		t.Errorf("expected a parse error of the expected JSON but found %v", err)
+	}
+
+	_, err = comparator.CompareEventually(context.Background(), []byte("{\"status\": \"DONE\"}"),
+		fetchSequence(nil, "{\"status\": "), PollPolicy{Interval: time.Millisecond, MaxAttempts: 2})
+	if !errors.Is(err, ErrPollingExhausted) || !strings.Contains(err.Error(), "unable to parse JSON") {
+		t.Errorf("expected a parse error of the actual JSON but found %v", err)
+	}
+}
+
+func TestPollPolicyBackoff(t *testing.T) {
+	policy := PollPolicy{Interval: 10 * time.Millisecond, Backoff: 2, MaxInterval: 50 * time.Millisecond}.withDefaults()
+
+	interval := policy.Interval
+	var intervals []time.Duration
+	for i := 0; i < 4; i++ {
+		interval = policy.nextInterval(interval)
+		intervals = append(intervals, interval)
+	}
+
+	expected := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	for i := range expected {
+		if intervals[i] != expected[i] {
+			t.Errorf("wrong intervals: %v", intervals)
+			break
+		}
+	}
+
+	if defaults := (PollPolicy{}).withDefaults(); defaults.nextInterval(defaults.Interval) != DefaultPollInterval {
+		t.Error("the default interval must be constant")
+	}
+}
+
+// fetchSequence returns the given payloads one after the other and then keeps returning the last one.
+// Empty payloads return the given error.
+func fetchSequence(err error, payloads ...string) func(ctx context.Context) ([]byte, error) {
+	attempt := 0
+	return func(ctx context.Context) ([]byte, error) {
+		payload := payloads[min(attempt, len(payloads)-1)]
+		attempt++
+		if payload == "" {
+			return nil, err
+		}
+		return []byte(payload), nil
+	}
+}
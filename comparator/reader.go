@@ -0,0 +1,91 @@
+package comparator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// DefaultMaxInputSize is the maximum number of bytes read from each input of [Comparator.CompareReader],
+// unless configured otherwise with [Builder.MaxInputSize].
+const DefaultMaxInputSize int64 = 10 << 20
+
+// ErrInputTooLarge is returned when an input of [Comparator.CompareReader] exceeds the maximum input size.
+var ErrInputTooLarge = errors.New("JSON input exceeds the maximum size")
+
+// CompareReader compares the JSON read from the expected & actual readers, for example files or
+// the body of an HTTP response. Each input is read entirely before it is decoded, so the memory used grows with
+// the size of the inputs, up to the limit configured with [Builder.MaxInputSize]. Use [Comparator.CompareStream]
+// for inputs that must not be held in memory.
+//
+// Each input may contain at most one JSON value.
+// If an input is larger than the limit, the returned error wraps [ErrInputTooLarge]. The readers are not closed.
+func (comparator *Comparator) CompareReader(expected io.Reader, actual io.Reader) (string, error) {
+	comparator.logger.Debug("json comparator - comparing readers")
+	if comparator.err != nil {
+		return "", comparator.err
+	}
+
+	expectedJsonObject, err1 := comparator.decodeJson("expected", expected)
+	if err1 != nil {
+		return "", err1
+	}
+
+	actualJsonObject, err2 := comparator.decodeJson("actual", actual)
+	if err2 != nil {
+		return "", err2
+	}
+
+	return comparator.compare(context.Background(), expectedJsonObject, actualJsonObject)
+}
+
+func (comparator *Comparator) decodeJson(name string, reader io.Reader) (any, error) {
+	if comparator.maxInputSize > 0 {
+		reader = &limitedReader{reader, comparator.maxInputSize}
+	}
+	decoder := json.NewDecoder(reader)
+
+	var result any
+	err := decoder.Decode(&result)
+	if err == nil {
+		if _, err = decoder.Token(); err == nil {
+			err = errors.New("unexpected data after the JSON value")
+		} else if err == io.EOF {
+			return result, nil
+		}
+	}
+
+	if errors.Is(err, ErrInputTooLarge) {
+		return nil, fmt.Errorf("unable to read %s JSON - %w - limit is [%d] bytes",
+			name, ErrInputTooLarge, comparator.maxInputSize)
+	}
+	return nil, handleError("unable to parse %s JSON - error [%s]", name, err)
+}
+
+// limitedReader works like io.LimitedReader, but fails with ErrInputTooLarge instead of returning io.EOF
+// when the limit is exceeded, so that a truncated input is not mistaken for an invalid JSON.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (reader *limitedReader) Read(buffer []byte) (int, error) {
+	if reader.remaining < 0 {
+		return 0, ErrInputTooLarge
+	}
+
+	// read one byte more than allowed, to detect inputs that exceed the limit
+	if reader.remaining < math.MaxInt64 && int64(len(buffer)) > reader.remaining+1 {
+		buffer = buffer[:reader.remaining+1]
+	}
+
+	n, err := reader.reader.Read(buffer)
+	reader.remaining -= int64(n)
+	if reader.remaining < 0 {
+		return n + int(reader.remaining), ErrInputTooLarge
+	}
+	return n, err
+}
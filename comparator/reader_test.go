@@ -0,0 +1,64 @@
+package comparator
+
+import (
+	"errors"
+	"github.com/go-clarum/clarum-json/recorder"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCompareReaderMatch(t *testing.T) {
+	comparator := NewComparator().Build()
+
+	_, err := comparator.CompareReader(strings.NewReader("{\"name\": \"Bruce\", \"gadgets\": [\"batarang\"]}"),
+		strings.NewReader("  {\"gadgets\": [\"batarang\"], \"name\": \"Bruce\"}\n"))
+
+	checkError(t, err, []string{})
+}
+
+func TestCompareReaderMismatch(t *testing.T) {
+	comparator := NewComparator().Recorder(recorder.NewDefaultRecorder()).Build()
+
+	recorderResult, err := comparator.CompareReader(strings.NewReader("{\"name\": \"Bruce\"}"),
+		strings.NewReader("{\"name\": \"Bruce Wayne\"}"))
+
+	checkError(t, err, []string{"[$.name] - value mismatch - expected [Bruce] but received [Bruce Wayne]"})
+	checkRecorderLog(t, "{\n  \"name\": Bruce Wayne, <-- value mismatch - expected [Bruce]\n}\n", recorderResult)
+}
+
+func TestCompareReaderInvalidJson(t *testing.T) {
+	comparator := NewComparator().Build()
+
+	_, err := comparator.CompareReader(strings.NewReader("{\"name\": \"Bruce\"}"), strings.NewReader("{\"name\": "))
+	checkError(t, err, []string{"unable to parse actual JSON - error [unexpected EOF]"})
+
+	_, err = comparator.CompareReader(strings.NewReader("{} {}"), strings.NewReader("{}"))
+	checkError(t, err, []string{"unable to parse expected JSON - error [unexpected data after the JSON value]"})
+}
+
+func TestCompareReaderMaxInputSize(t *testing.T) {
+	comparator := NewComparator().MaxInputSize(20).Build()
+
+	_, err := comparator.CompareReader(strings.NewReader("{\"name\": \"Bruce\"}"), strings.NewReader("{\"name\":\"Bruce\"}"))
+	checkError(t, err, []string{})
+
+	_, err = comparator.CompareReader(strings.NewReader("{\"name\": \"Bruce\"}"),
+		strings.NewReader("{\"name\": \"Bruce Wayne\"}"))
+	checkError(t, err, []string{"unable to read actual JSON - JSON input exceeds the maximum size - limit is [20] bytes"})
+	if !errors.Is(err, ErrInputTooLarge) {
+		t.Error("error must wrap ErrInputTooLarge")
+	}
+
+	unlimited := NewComparator().MaxInputSize(0).Build()
+	_, err = unlimited.CompareReader(strings.NewReader("[\""+strings.Repeat("a", 1024)+"\"]"),
+		strings.NewReader("[\""+strings.Repeat("a", 1024)+"\"]"))
+	checkError(t, err, []string{})
+}
+
+func TestCompareReaderMaxInt64InputSize(t *testing.T) {
+	comparator := NewComparator().MaxInputSize(math.MaxInt64).Build()
+
+	_, err := comparator.CompareReader(strings.NewReader("{\"name\": \"Bruce\"}"), strings.NewReader("{\"name\":\"Bruce\"}"))
+	checkError(t, err, []string{})
+}
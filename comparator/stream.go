@@ -0,0 +1,495 @@
+package comparator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-json/internal/matcher"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"io"
+	"reflect"
+	"slices"
+)
+
+// CompareStream compares the JSON read from the expected & actual readers token by token, without decoding
+// the documents first. It is meant for very large documents, like exports of hundreds of megabytes,
+// which are not limited by [Builder.MaxInputSize].
+//
+// Both documents are read in parallel, so the memory needed depends on the depth of the documents and not
+// on their size, as long as the fields of the objects appear in the same order in both documents.
+// When the fields of an object are in a different order, the remaining fields of that object are decoded and
+// compared like in [Comparator.Compare].
+//
+// The errors are the same as the ones returned by [Comparator.Compare], except for array size mismatches,
+// which contain the lengths of the arrays instead of their values and cannot be converted into a [Patch].
+// The recorder is not used, as its output would grow with the size of the documents.
+func (comparator *Comparator) CompareStream(expected io.Reader, actual io.Reader) error {
+	return comparator.CompareStreamContext(context.Background(), expected, actual)
+}
+
+// CompareStreamContext is like [Comparator.CompareStream], but stops the comparison when the context is done,
+// like [Comparator.CompareContext]. The limits of the comparator are applied in the same way, except that
+// the length of an array is only known once it was read: the mismatches found in an array that turns out
+// to be too long are dropped, but a comparison stopped inside of it is not reported as a [LimitError].
+// Once the comparison is stopped, the rest of the documents is not validated.
+func (comparator *Comparator) CompareStreamContext(ctx context.Context, expected io.Reader, actual io.Reader) error {
+	comparator.logger.Debug("json comparator - comparing streams")
+	if comparator.err != nil {
+		return comparator.err
+	}
+	if len(comparator.pathsToIgnore) > 0 {
+		return errIgnoreNotSupported
+	}
+
+	// the tree comparison is reused for objects with fields in a different order, without recording it
+	streamComparator := comparator.withoutRecorder().begin(ctx)
+
+	expectedStream := newTokenStream("expected", expected)
+	actualStream := newTokenStream("actual", actual)
+
+	compareErrors, err := streamComparator.compareStreams(jsonpath.RootPath, jsonpath.RootPointer, "",
+		expectedStream, actualStream, nil)
+	if err == nil && streamComparator.traversal.err == nil {
+		err = expectedStream.end()
+	}
+	if err == nil && streamComparator.traversal.err == nil {
+		err = actualStream.end()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(compareErrors) > 0 {
+		comparator.logger.Debug("json comparator - JSON streams do not match")
+	} else {
+		comparator.logger.Debug("json comparator - JSON streams match")
+	}
+
+	return streamComparator.result(compareErrors)
+}
+
+func (comparator *Comparator) compareStreams(path string, pointer string, fieldName string,
+	expected *tokenStream, actual *tokenStream, compareErrors []error) ([]error, error) {
+	expectedToken, err := expected.next()
+	if err != nil {
+		return nil, err
+	}
+
+	actualToken, err := actual.next()
+	if err != nil {
+		return nil, err
+	}
+
+	return comparator.compareTokens(path, pointer, fieldName, expectedToken, actualToken, expected, actual,
+		compareErrors)
+}
+
+// compareTokens compares the values that start with the given tokens. Objects & arrays are compared
+// while their remaining tokens are read from the streams.
+func (comparator *Comparator) compareTokens(path string, pointer string, fieldName string,
+	expectedToken json.Token, actualToken json.Token, expected *tokenStream, actual *tokenStream,
+	compareErrors []error) ([]error, error) {
+	if matcher.IsIgnore(expectedToken) {
+		return compareErrors, actual.skip(actualToken)
+	}
+
+	expectedKind := tokenKind(expectedToken)
+	if expectedKind != tokenKind(actualToken) {
+		expectedValue, err := expected.value(expectedToken)
+		if err != nil {
+			return nil, err
+		}
+
+		actualValue, err := actual.value(actualToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if jsonpath.IsRoot(path) {
+			return handleRootTypeMismatch(path, pointer, fieldName, expectedValue, actualValue,
+				comparator.recorder, compareErrors), nil
+		} else if jsonpath.IsChildOfArray(path) {
+			return handleArrayItemTypeMismatch(path, pointer, expectedValue, actualValue, comparator.recorder, "",
+				compareErrors), nil
+		}
+		return handleTypeMismatch(path, pointer, fieldName, expectedValue, actualValue, comparator.recorder,
+			compareErrors), nil
+	}
+
+	switch expectedKind {
+	case reflect.Map, reflect.Slice:
+		if !comparator.enter(path, compareErrors) {
+			return compareErrors, comparator.skipStream(expected, expectedKind == reflect.Map)
+		}
+		defer comparator.leave()
+
+		if expectedKind == reflect.Map {
+			return comparator.compareObjectStreams(path, pointer, expected, actual, compareErrors)
+		}
+		return comparator.compareArrayStreams(path, pointer, expected, actual, compareErrors)
+	default:
+		return compareValue(path, pointer, fieldName, expectedToken, actualToken, comparator.recorder, "",
+			compareErrors), nil
+	}
+}
+
+// compareObjectStreams compares the fields of two objects as long as they appear in the same order.
+// From the first field that differs, the remaining fields of both objects are decoded and compared by their keys.
+func (comparator *Comparator) compareObjectStreams(path string, pointer string, expected *tokenStream,
+	actual *tokenStream, compareErrors []error) ([]error, error) {
+	objectErrorsStart := len(compareErrors)
+
+	for fieldCount := 0; ; fieldCount++ {
+		if comparator.stopped(compareErrors) {
+			return compareErrors, comparator.skipStream(expected, true)
+		}
+
+		expectedKey, expectedEnd, err := expected.key()
+		if err != nil {
+			return nil, err
+		}
+
+		actualKey, actualEnd, err := actual.key()
+		if err != nil {
+			return nil, err
+		}
+
+		if expectedEnd && actualEnd {
+			return compareErrors, nil
+		}
+
+		if expectedEnd || actualEnd || expectedKey != actualKey {
+			expectedFields, err := expected.remainingFields(expectedKey, expectedEnd)
+			if err != nil {
+				return nil, err
+			}
+
+			actualFields, err := actual.remainingFields(actualKey, actualEnd)
+			if err != nil {
+				return nil, err
+			}
+
+			return comparator.compareRemainingFields(path, pointer, fieldCount, expectedFields, actualFields,
+				objectErrorsStart, compareErrors), nil
+		}
+
+		compareErrors, err = comparator.compareStreams(jsonpath.GetObjectChildPath(path, expectedKey),
+			jsonpath.GetObjectChildPointer(pointer, expectedKey), expectedKey, expected, actual, compareErrors)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (comparator *Comparator) compareRemainingFields(path string, pointer string, comparedFieldCount int,
+	expected map[string]any, actual map[string]any, objectErrorsStart int, compareErrors []error) []error {
+	for key, expectedValue := range expected {
+		if comparator.stopped(compareErrors) {
+			comparator.skip(expectedValue)
+			continue
+		}
+
+		childPath := jsonpath.GetObjectChildPath(path, key)
+		childPointer := jsonpath.GetObjectChildPointer(pointer, key)
+
+		if actualValue, exists := actual[key]; exists {
+			compareErrors = comparator.compareRoot(childPath, childPointer, key, expectedValue, actualValue,
+				compareErrors)
+		} else {
+			compareErrors = handleMissingField(childPath, childPointer, key, expectedValue, "",
+				comparator.recorder, compareErrors)
+		}
+	}
+
+	if comparator.strictObjectCheck && !comparator.stopped(compareErrors) {
+		compareErrors = handleUnexpectedFields(path, pointer, expected, actual, comparator.recorder, "",
+			compareErrors)
+
+		// like in Compare, the field count mismatch is reported before the mismatches of the fields
+		expectedFieldCount := comparedFieldCount + len(expected)
+		actualFieldCount := comparedFieldCount + len(actual)
+		if expectedFieldCount != actualFieldCount {
+			compareErrors = slices.Insert(compareErrors, objectErrorsStart, error(&Mismatch{FieldCountMismatch,
+				path, pointer, expectedFieldCount, actualFieldCount,
+				fmt.Sprintf("[%s] - number of fields does not match", path)}))
+		}
+	}
+
+	return compareErrors
+}
+
+// compareArrayStreams compares the items of two arrays. Like in Compare, if the arrays have different sizes,
+// only the size mismatch is reported.
+func (comparator *Comparator) compareArrayStreams(path string, pointer string, expected *tokenStream,
+	actual *tokenStream, compareErrors []error) ([]error, error) {
+	arrayErrorsStart := len(compareErrors)
+
+	for index := 0; ; index++ {
+		if comparator.stopped(compareErrors) {
+			return compareErrors, comparator.skipStream(expected, false)
+		}
+
+		expectedToken, err := expected.next()
+		if err != nil {
+			return nil, err
+		}
+
+		actualToken, err := actual.next()
+		if err != nil {
+			return nil, err
+		}
+
+		expectedEnd := expectedToken == json.Delim(']')
+		actualEnd := actualToken == json.Delim(']')
+		if expectedEnd && actualEnd {
+			return compareErrors, nil
+		}
+
+		// the lengths are not known yet, so the mismatches found in an array that is too long are dropped
+		if maxArrayLength := comparator.maxArrayLength; maxArrayLength > 0 && index >= maxArrayLength {
+			comparator.traversal.err = &LimitError{ErrMaxArrayLengthExceeded, path, maxArrayLength}
+			return compareErrors[:arrayErrorsStart], nil
+		}
+
+		if expectedEnd || actualEnd {
+			expectedLen, err := expected.arrayLength(index, expectedToken)
+			if err != nil {
+				return nil, err
+			}
+
+			actualLen, err := actual.arrayLength(index, actualToken)
+			if err != nil {
+				return nil, err
+			}
+
+			if !comparator.checkArrayLength(path, expectedLen, actualLen) {
+				return compareErrors[:arrayErrorsStart], nil
+			}
+
+			return append(compareErrors[:arrayErrorsStart], &Mismatch{ArraySizeMismatch, path, pointer,
+				expectedLen, actualLen,
+				fmt.Sprintf("[%s] - array size mismatch - expected [%d] but received [%d]", path, expectedLen, actualLen)}), nil
+		}
+
+		compareErrors, err = comparator.compareTokens(jsonpath.GetArrayIndexPath(path, index),
+			jsonpath.GetArrayIndexPointer(pointer, index), "", expectedToken, actualToken, expected, actual,
+			compareErrors)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// skipStream counts the remaining values of the object or array of the expected stream that is compared when
+// the comparison is stopped, so that a [TruncatedError] contains the number of skipped checks.
+// Any other reason to stop the comparison is returned without reading more of the documents.
+func (comparator *Comparator) skipStream(expected *tokenStream, object bool) error {
+	if _, isTruncated := comparator.traversal.err.(*TruncatedError); !isTruncated {
+		return nil
+	}
+
+	for {
+		if object {
+			_, end, err := expected.key()
+			if err != nil || end {
+				return err
+			}
+		}
+
+		token, err := expected.next()
+		if err != nil {
+			return err
+		}
+		if !object && token == json.Delim(']') {
+			return nil
+		}
+
+		count, err := expected.countValues(token)
+		if err != nil {
+			return err
+		}
+		comparator.traversal.skipped += count
+	}
+}
+
+// tokenKind returns the kind of the value that starts with the token, using the same kinds
+// as the values created by json.Unmarshal.
+func tokenKind(token json.Token) reflect.Kind {
+	switch token {
+	case json.Delim('{'):
+		return reflect.Map
+	case json.Delim('['):
+		return reflect.Slice
+	default:
+		return kindOf(token)
+	}
+}
+
+// tokenStream reads the tokens of one of the compared documents.
+type tokenStream struct {
+	name    string
+	decoder *json.Decoder
+}
+
+func newTokenStream(name string, reader io.Reader) *tokenStream {
+	return &tokenStream{name, json.NewDecoder(reader)}
+}
+
+func (stream *tokenStream) next() (json.Token, error) {
+	token, err := stream.decoder.Token()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, stream.error(err)
+	}
+	return token, nil
+}
+
+// key reads the key of the next field of an object, or the end of the object.
+func (stream *tokenStream) key() (string, bool, error) {
+	token, err := stream.next()
+	if err != nil {
+		return "", false, err
+	}
+
+	if token == json.Delim('}') {
+		return "", true, nil
+	}
+	return token.(string), false, nil
+}
+
+// value decodes the value that starts with the token.
+func (stream *tokenStream) value(token json.Token) (any, error) {
+	switch token {
+	case json.Delim('{'):
+		key, end, err := stream.key()
+		if err != nil {
+			return nil, err
+		}
+		return stream.remainingFields(key, end)
+	case json.Delim('['):
+		array := []any{}
+		for stream.decoder.More() {
+			var item any
+			if err := stream.decoder.Decode(&item); err != nil {
+				return nil, stream.error(err)
+			}
+			array = append(array, item)
+		}
+		_, err := stream.next()
+		return array, err
+	default:
+		return token, nil
+	}
+}
+
+// remainingFields decodes the fields of an object, starting with the field of the key that was already read.
+func (stream *tokenStream) remainingFields(key string, end bool) (map[string]any, error) {
+	fields := map[string]any{}
+
+	for !end {
+		var value any
+		if err := stream.decoder.Decode(&value); err != nil {
+			return nil, stream.error(err)
+		}
+		fields[key] = value
+
+		var err error
+		if key, end, err = stream.key(); err != nil {
+			return nil, err
+		}
+	}
+
+	return fields, nil
+}
+
+// arrayLength skips the remaining items of an array and returns its length, given the number of items already read
+// and the token that was read after them.
+func (stream *tokenStream) arrayLength(index int, token json.Token) (int, error) {
+	for ; token != json.Delim(']'); index++ {
+		if err := stream.skip(token); err != nil {
+			return 0, err
+		}
+
+		var err error
+		if token, err = stream.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	return index, nil
+}
+
+// countValues reads the remaining tokens of the value that starts with the token and counts the values it contains,
+// like countValues does for a decoded value.
+func (stream *tokenStream) countValues(token json.Token) (int, error) {
+	if token != json.Delim('{') && token != json.Delim('[') {
+		return 1, nil
+	}
+
+	count := 1
+	for {
+		if token == json.Delim('{') {
+			_, end, err := stream.key()
+			if err != nil || end {
+				return count, err
+			}
+		}
+
+		child, err := stream.next()
+		if err != nil {
+			return 0, err
+		}
+		if child == json.Delim(']') {
+			return count, nil
+		}
+
+		childCount, err := stream.countValues(child)
+		if err != nil {
+			return 0, err
+		}
+		count += childCount
+	}
+}
+
+// skip reads the remaining tokens of the value that starts with the token.
+func (stream *tokenStream) skip(token json.Token) error {
+	if token != json.Delim('{') && token != json.Delim('[') {
+		return nil
+	}
+
+	for depth := 1; depth > 0; {
+		token, err := stream.next()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// end checks that there is no data after the compared JSON value.
+func (stream *tokenStream) end() error {
+	_, err := stream.decoder.Token()
+	if err == io.EOF {
+		return nil
+	}
+
+	if err == nil {
+		err = errors.New("unexpected data after the JSON value")
+	}
+	return stream.error(err)
+}
+
+func (stream *tokenStream) error(err error) error {
+	return handleError("unable to parse %s JSON - error [%s]", stream.name, err)
+}
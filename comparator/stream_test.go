@@ -0,0 +1,277 @@
+package comparator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCompareStreamMatch(t *testing.T) {
+	testCompareStream(t, true,
+		"{\"name\": \"Bruce\", \"gadgets\": [\"batarang\", {\"name\": \"grapnel gun\"}], \"age\": 37, \"active\": true}",
+		"{\"name\": \"Bruce\", \"gadgets\": [\"batarang\", {\"name\": \"grapnel gun\"}], \"age\": 37, \"active\": true}",
+		[]string{})
+}
+
+func TestCompareStreamFieldsInDifferentOrder(t *testing.T) {
+	testCompareStream(t, true,
+		"{\"name\": \"Bruce\", \"location\": {\"street\": \"Mountain Drive\", \"number\": 1007}, \"age\": 37}",
+		"{\"name\": \"Bruce\", \"age\": 37, \"location\": {\"number\": 1007, \"street\": \"Mountain Drive\"}}",
+		[]string{})
+}
+
+func TestCompareStreamIgnore(t *testing.T) {
+	testCompareStream(t, true,
+		"{\"id\": \"@ignore@\", \"items\": [\"@ignore@\", 2], \"meta\": \"@ignore@\"}",
+		"{\"id\": 17, \"items\": [{\"a\": [1, 2]}, 2], \"meta\": {\"version\": [3]}}",
+		[]string{})
+}
+
+func TestCompareStreamNull(t *testing.T) {
+	testCompareStream(t, true,
+		"{\"a\": null, \"b\": [null, 1], \"c\": \"x\"}",
+		"{\"a\": null, \"b\": [\"y\", 1], \"c\": null}",
+		[]string{
+			"[$.b[0]] - value type mismatch - expected [null] but found [string]",
+			"[$.c] - type mismatch - expected [string] but found [null]",
+		})
+}
+
+func TestCompareStreamArraySizeMismatch(t *testing.T) {
+	testCompareStream(t, true,
+		"{\"items\": [1, 2, [3]], \"other\": [1]}",
+		"{\"items\": [1, 5, [3], {\"a\": 1}, 6], \"other\": []}",
+		[]string{
+			"[$.items] - array size mismatch - expected [3] but received [5]",
+			"[$.other] - array size mismatch - expected [1] but received [0]",
+		})
+}
+
+func TestCompareStreamLikeCompare(t *testing.T) {
+	testCases := [][2]string{
+		{"{\"a\": 1, \"b\": \"x\", \"c\": true}", "{\"a\": 2, \"b\": \"y\", \"c\": false}"},
+		{"{\"a\": 1, \"b\": 2}", "{\"a\": 1, \"c\": 3}"},
+		{"{\"a\": 1, \"b\": 2, \"c\": 3}", "{\"a\": 1, \"c\": 4}"},
+		{"{\"a\": 1}", "{\"a\": 1, \"b\": {\"c\": 3}}"},
+		{"{\"a\": {\"b\": [1, {\"c\": 2}]}}", "{\"a\": {\"b\": [1, {\"c\": 3}]}}"},
+		{"{\"a\": [1, 2], \"b\": {\"c\": 1}}", "{\"b\": {\"c\": 2}, \"a\": [1]}"},
+		{"{\"a\": {\"b\": 1}}", "{\"a\": [1]}"},
+		{"[1, \"a\", {\"b\": 1}]", "[1, 2, {\"b\": \"c\"}]"},
+		{"[{\"a\": [1, 2]}]", "[{\"a\": [1, 3, 4]}]"},
+		{"{\"a\": 1}", "[1]"},
+		{"\"a\"", "\"b\""},
+	}
+
+	for _, strict := range []bool{true, false} {
+		comparator := NewComparator().StrictObjectCheck(strict).Build()
+
+		for _, testCase := range testCases {
+			_, err := comparator.Compare([]byte(testCase[0]), []byte(testCase[1]))
+			streamErr := comparator.CompareStream(strings.NewReader(testCase[0]), strings.NewReader(testCase[1]))
+
+			if !slices.Equal(sortedErrorMessages(err), sortedErrorMessages(streamErr)) {
+				t.Errorf("errors of [%s] and [%s] do not match:\n%v\n%v", testCase[0], testCase[1], err, streamErr)
+			}
+		}
+	}
+}
+
+func TestCompareStreamLimitsLikeCompare(t *testing.T) {
+	testCases := []struct {
+		builder  *Builder
+		expected string
+		actual   string
+	}{
+		{NewComparator().MaxDepth(2), "{\"a\": {\"b\": {\"c\": 1}}}", "{\"a\": {\"b\": {\"c\": 2}}}"},
+		{NewComparator().MaxDepth(2), "{\"a\": [1], \"b\": 1}", "{\"a\": [2], \"b\": 2}"},
+		{NewComparator().MaxDepth(2), "[[[1]], 2]", "[[[1]], 3]"},
+		{NewComparator().MaxArrayLength(2), "{\"a\": [1, 2, 3]}", "{\"a\": [4, 5, 6]}"},
+		{NewComparator().MaxArrayLength(2), "{\"a\": [1, 2]}", "{\"a\": [1, 2, 3]}"},
+		{NewComparator().MaxArrayLength(2), "{\"a\": [1]}", "{\"a\": [1, 2, 3]}"},
+		{NewComparator().MaxArrayLength(2), "{\"a\": [1]}", "{\"a\": [2, 3]}"},
+		{NewComparator().StopAfter(1), "[1, 2, [3, 4], {\"a\": 5}]", "[0, 0, [0, 0], {\"a\": 0}]"},
+		{NewComparator().StopAfter(2), "[1, [2, [3, 4]], 5, {\"a\": [6]}]", "[1, [0, [0, 4]], 0, {\"a\": [0]}]"},
+		{NewComparator().StopAfter(1), "[1, 2]", "[1, 2]"},
+		{NewComparator().MaxErrors(1), "[1, 2, [3]]", "[0, 0, [0]]"},
+		{NewComparator().MaxDepth(2).MaxArrayLength(2).StopAfter(1),
+			"{\"a\": {\"b\": {\"c\": [1, 2, 3]}}}", "{\"a\": {\"b\": {\"c\": [1, 2, 3]}}}"},
+	}
+
+	for _, testCase := range testCases {
+		comparator := testCase.builder.Build()
+
+		_, err := comparator.Compare([]byte(testCase.expected), []byte(testCase.actual))
+		streamErr := comparator.CompareStream(strings.NewReader(testCase.expected), strings.NewReader(testCase.actual))
+
+		if !slices.Equal(sortedErrorLines(err), sortedErrorLines(streamErr)) {
+			t.Errorf("errors of [%s] and [%s] do not match:\n%v\n%v", testCase.expected, testCase.actual,
+				err, streamErr)
+		}
+	}
+}
+
+func TestCompareStreamContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := NewComparator().Build().CompareStreamContext(ctx, strings.NewReader("{\"a\": [1]}"),
+		strings.NewReader("{\"a\": [2]}"))
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a context error but found %v", err)
+	}
+}
+
+func TestCompareStreamInvalidJson(t *testing.T) {
+	comparator := NewComparator().Build()
+
+	err := comparator.CompareStream(strings.NewReader("{\"a\": [1, 2]}"), strings.NewReader("{\"a\": [1, "))
+	checkError(t, err, []string{"unable to parse actual JSON - error [unexpected EOF]"})
+
+	err = comparator.CompareStream(strings.NewReader("{\"a\": 1} 2"), strings.NewReader("{\"a\": 1}"))
+	checkError(t, err, []string{"unable to parse expected JSON - error [unexpected data after the JSON value]"})
+
+	err = comparator.CompareStream(strings.NewReader("{\"a\" 1}"), strings.NewReader("{\"a\": 1}"))
+	checkError(t, err, []string{"unable to parse expected JSON - error [invalid character '1' after object key]"})
+}
+
+func testCompareStream(t *testing.T, strict bool, expected string, actual string, expectedErrors []string) {
+	comparator := NewComparator().StrictObjectCheck(strict).Build()
+
+	err := comparator.CompareStream(strings.NewReader(expected), strings.NewReader(actual))
+
+	checkError(t, err, expectedErrors)
+	if len(Mismatches(err)) != len(expectedErrors) {
+		t.Errorf("expected %d errors but found: %v", len(expectedErrors), err)
+	}
+}
+
+func sortedErrorLines(err error) []string {
+	if err == nil {
+		return nil
+	}
+
+	lines := strings.Split(err.Error(), "\n")
+	slices.Sort(lines)
+	return lines
+}
+
+func sortedErrorMessages(err error) []string {
+	var messages []string
+	for _, mismatch := range Mismatches(err) {
+		messages = append(messages, mismatch.Error())
+	}
+	slices.Sort(messages)
+	return messages
+}
+
+// The benchmarks compare documents that grow in size & in depth. Besides the usual allocation statistics,
+// they report the peak of the heap during a comparison as 'peak-heap-B', which grows with the size of the documents
+// for CompareReader but only with their depth for CompareStream.
+
+func BenchmarkCompareStream(b *testing.B) {
+	benchmarkDocuments(b, func(comparator *Comparator, expected io.Reader, actual io.Reader) {
+		_ = comparator.CompareStream(expected, actual)
+	})
+}
+
+func BenchmarkCompareReader(b *testing.B) {
+	benchmarkDocuments(b, func(comparator *Comparator, expected io.Reader, actual io.Reader) {
+		_, _ = comparator.CompareReader(expected, actual)
+	})
+}
+
+func benchmarkDocuments(b *testing.B, compare func(comparator *Comparator, expected io.Reader, actual io.Reader)) {
+	comparator := NewComparator().MaxInputSize(0).Build()
+
+	for _, items := range []int{1_000, 10_000, 100_000} {
+		document := longDocument(items)
+		b.Run(fmt.Sprintf("items=%d", items), func(b *testing.B) {
+			benchmarkCompare(b, comparator, document, compare)
+		})
+	}
+
+	for _, depth := range []int{10, 100, 1_000} {
+		document := deepDocument(depth)
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			benchmarkCompare(b, comparator, document, compare)
+		})
+	}
+}
+
+func benchmarkCompare(b *testing.B, comparator *Comparator, document []byte,
+	compare func(comparator *Comparator, expected io.Reader, actual io.Reader)) {
+	b.SetBytes(int64(2 * len(document)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		compare(comparator, bytes.NewReader(document), bytes.NewReader(document))
+	}
+
+	b.ReportMetric(float64(peakHeap(func(expected io.Reader, actual io.Reader) {
+		compare(comparator, expected, actual)
+	}, document)), "peak-heap-B")
+}
+
+// peakHeap runs the comparison once and samples the live heap while the documents are read.
+func peakHeap(compare func(expected io.Reader, actual io.Reader), document []byte) uint64 {
+	baseline := liveHeap()
+	sampler := &heapSampler{baseline: baseline}
+
+	compare(&sampledReader{bytes.NewReader(document), sampler}, &sampledReader{bytes.NewReader(document), sampler})
+	return sampler.peak
+}
+
+type heapSampler struct {
+	baseline uint64
+	reads    int
+	peak     uint64
+}
+
+// sampledReader samples the heap on every 16th read; the json.Decoder reads chunks of at least 512 bytes.
+type sampledReader struct {
+	reader  io.Reader
+	sampler *heapSampler
+}
+
+func (reader *sampledReader) Read(buffer []byte) (int, error) {
+	reader.sampler.reads++
+	if reader.sampler.reads%16 == 0 {
+		if heap := liveHeap(); heap > reader.sampler.baseline && heap-reader.sampler.baseline > reader.sampler.peak {
+			reader.sampler.peak = heap - reader.sampler.baseline
+		}
+	}
+	return reader.reader.Read(buffer)
+}
+
+func liveHeap() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// longDocument creates an object with an array of objects.
+func longDocument(items int) []byte {
+	var document bytes.Buffer
+	document.WriteString("{\"items\": [")
+	for i := 0; i < items; i++ {
+		if i > 0 {
+			document.WriteString(", ")
+		}
+		_, _ = fmt.Fprintf(&document, "{\"id\": %d, \"name\": \"item %d\", \"active\": true, \"tags\": [\"a\", \"b\"]}", i, i)
+	}
+	document.WriteString("]}")
+	return document.Bytes()
+}
+
+// deepDocument creates nested objects.
+func deepDocument(depth int) []byte {
+	return []byte(strings.Repeat("{\"id\": 1, \"child\": ", depth) + "{}" + strings.Repeat("}", depth))
+}
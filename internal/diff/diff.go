@@ -0,0 +1,260 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Operation int
+
+const (
+	Equal Operation = iota
+	Delete
+	Insert
+)
+
+type Edit struct {
+	Operation Operation
+	Line      string
+}
+
+// Lines returns the shortest edit script that transforms the 'from' lines into the 'to' lines.
+// It uses the linear space variant of the Myers diff algorithm, which is also used by git & GNU diff:
+// the middle snake of the edit graph splits the problem in two halves that are solved recursively,
+// so the memory usage only grows with the number of lines and not with the number of differences.
+func Lines(from []string, to []string) []Edit {
+	n, m := len(from), len(to)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	size := 2*((n+m+1)/2) + 3
+	differ := &differ{
+		from:     from,
+		to:       to,
+		forward:  make([]int, size),
+		backward: make([]int, size),
+		edits:    make([]Edit, 0, max(n, m)),
+	}
+	differ.compare(0, n, 0, m)
+
+	return deletesFirst(differ.edits)
+}
+
+// deletesFirst moves the deleted lines in front of the inserted lines of every block of consecutive changes,
+// so that a replaced line is always shown as removed and then added, like git does.
+func deletesFirst(edits []Edit) []Edit {
+	for start := 0; start < len(edits); start++ {
+		if edits[start].Operation == Equal {
+			continue
+		}
+		end := lastChange(edits, start) + 1
+		sort.SliceStable(edits[start:end], func(i, j int) bool {
+			return edits[start+i].Operation == Delete && edits[start+j].Operation == Insert
+		})
+		start = end
+	}
+	return edits
+}
+
+type differ struct {
+	from     []string
+	to       []string
+	forward  []int
+	backward []int
+	edits    []Edit
+}
+
+func (differ *differ) compare(fromStart int, fromEnd int, toStart int, toEnd int) {
+	for fromStart < fromEnd && toStart < toEnd && differ.from[fromStart] == differ.to[toStart] {
+		differ.edits = append(differ.edits, Edit{Equal, differ.from[fromStart]})
+		fromStart++
+		toStart++
+	}
+	suffix := 0
+	for fromStart < fromEnd-suffix && toStart < toEnd-suffix &&
+		differ.from[fromEnd-suffix-1] == differ.to[toEnd-suffix-1] {
+		suffix++
+	}
+	fromEnd -= suffix
+	toEnd -= suffix
+
+	if fromStart == fromEnd {
+		for _, line := range differ.to[toStart:toEnd] {
+			differ.edits = append(differ.edits, Edit{Insert, line})
+		}
+	} else if toStart == toEnd {
+		for _, line := range differ.from[fromStart:fromEnd] {
+			differ.edits = append(differ.edits, Edit{Delete, line})
+		}
+	} else {
+		// without a common prefix & suffix both halves are smaller than the whole, so the recursion ends
+		snakeFromStart, snakeToStart, snakeFromEnd := differ.middleSnake(fromStart, fromEnd, toStart, toEnd)
+		snakeToEnd := snakeToStart + snakeFromEnd - snakeFromStart
+
+		differ.compare(fromStart, snakeFromStart, toStart, snakeToStart)
+		for _, line := range differ.from[snakeFromStart:snakeFromEnd] {
+			differ.edits = append(differ.edits, Edit{Equal, line})
+		}
+		differ.compare(snakeFromEnd, fromEnd, snakeToEnd, toEnd)
+	}
+
+	for _, line := range differ.from[fromEnd : fromEnd+suffix] {
+		differ.edits = append(differ.edits, Edit{Equal, line})
+	}
+}
+
+// middleSnake searches the shortest path from both corners of the edit graph at the same time
+// and returns the snake where the two searches overlap, as its start and its end on the 'from' lines.
+func (differ *differ) middleSnake(fromStart int, fromEnd int, toStart int, toEnd int) (int, int, int) {
+	n, m := fromEnd-fromStart, toEnd-toStart
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+
+	// the diagonals go from -limit to limit and are shifted by the offset
+	offset := limit + 1
+	forward := differ.forward[:2*offset+1]
+	backward := differ.backward[:2*offset+1]
+	forward[offset+1] = 0
+	backward[offset+1] = 0
+
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			x := nextX(forward, offset, k, d)
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && differ.from[fromStart+x] == differ.to[toStart+y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			if odd && k >= delta-(d-1) && k <= delta+(d-1) && x+backward[offset+delta-k] >= n {
+				return fromStart + startX, toStart + startY, fromStart + x
+			}
+		}
+
+		// the backward search works on the reversed lines, its diagonal k matches the diagonal delta-k
+		for k := -d; k <= d; k += 2 {
+			x := nextX(backward, offset, k, d)
+			y := x - k
+			startX := x
+			for x < n && y < m && differ.from[fromEnd-x-1] == differ.to[toEnd-y-1] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			if !odd && delta-k >= -d && delta-k <= d && x+forward[offset+delta-k] >= n {
+				return fromEnd - x, toEnd - y, fromEnd - startX
+			}
+		}
+	}
+
+	panic("diff: the searches from both corners of the edit graph did not overlap")
+}
+
+// nextX returns the x where the path of length d on the diagonal k starts,
+// which is one step away from the furthest path of length d-1 on a neighbouring diagonal.
+func nextX(furthest []int, offset int, k int, d int) int {
+	if k == -d || (k != d && furthest[offset+k-1] < furthest[offset+k+1]) {
+		return furthest[offset+k+1]
+	}
+	return furthest[offset+k-1] + 1
+}
+
+// Unified returns the differences between the 'from' and the 'to' lines in the unified diff format.
+// Each hunk contains the given number of unchanged lines around the changes.
+// If there are no differences, an empty string is returned.
+func Unified(fromName string, toName string, from []string, to []string, contextLines int) string {
+	edits := Lines(from, to)
+
+	var result strings.Builder
+	for start := nextChange(edits, 0); start < len(edits); start = nextChange(edits, start) {
+		hunkStart := max(start-contextLines, 0)
+		end := start
+		// merge changes that are close enough to share their context lines
+		for next := nextChange(edits, end+1); next < len(edits) && next-lastChange(edits, end)-1 <= 2*contextLines; {
+			end = next
+			next = nextChange(edits, end+1)
+		}
+		end = lastChange(edits, end)
+		hunkEnd := min(end+contextLines+1, len(edits))
+
+		if result.Len() == 0 {
+			result.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", fromName, toName))
+		}
+		writeHunk(&result, edits, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return result.String()
+}
+
+func writeHunk(result *strings.Builder, edits []Edit, hunkStart int, hunkEnd int) {
+	fromLine, toLine := 0, 0
+	for _, edit := range edits[:hunkStart] {
+		fromLine, toLine = advance(edit, fromLine, toLine)
+	}
+
+	fromLength, toLength := 0, 0
+	for _, edit := range edits[hunkStart:hunkEnd] {
+		fromLength, toLength = advance(edit, fromLength, toLength)
+	}
+
+	result.WriteString(fmt.Sprintf("@@ -%s +%s @@\n", formatRange(fromLine, fromLength),
+		formatRange(toLine, toLength)))
+	for _, edit := range edits[hunkStart:hunkEnd] {
+		switch edit.Operation {
+		case Equal:
+			result.WriteString(" ")
+		case Delete:
+			result.WriteString("-")
+		case Insert:
+			result.WriteString("+")
+		}
+		result.WriteString(edit.Line)
+		result.WriteString("\n")
+	}
+}
+
+func advance(edit Edit, fromLine int, toLine int) (int, int) {
+	switch edit.Operation {
+	case Equal:
+		return fromLine + 1, toLine + 1
+	case Delete:
+		return fromLine + 1, toLine
+	default:
+		return fromLine, toLine + 1
+	}
+}
+
+// formatRange follows the GNU diff conventions: the length is omitted if it is 1
+// and an empty range starts at the line before it.
+func formatRange(linesBefore int, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", linesBefore)
+	} else if length == 1 {
+		return fmt.Sprintf("%d", linesBefore+1)
+	}
+	return fmt.Sprintf("%d,%d", linesBefore+1, length)
+}
+
+func nextChange(edits []Edit, from int) int {
+	for i := from; i < len(edits); i++ {
+		if edits[i].Operation != Equal {
+			return i
+		}
+	}
+	return len(edits)
+}
+
+// lastChange returns the index of the last change in the block of consecutive changes that contains the given index.
+func lastChange(edits []Edit, index int) int {
+	for index+1 < len(edits) && edits[index+1].Operation != Equal {
+		index++
+	}
+	return index
+}
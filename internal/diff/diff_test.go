@@ -0,0 +1,144 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLinesEqual(t *testing.T) {
+	edits := Lines([]string{"a", "b"}, []string{"a", "b"})
+
+	if len(edits) != 2 || edits[0].Operation != Equal || edits[1].Operation != Equal {
+		t.Errorf("wrong edits: %v", edits)
+	}
+}
+
+func TestLinesEmpty(t *testing.T) {
+	if edits := Lines(nil, nil); len(edits) != 0 {
+		t.Errorf("wrong edits: %v", edits)
+	}
+	if edits := Lines(nil, []string{"a"}); len(edits) != 1 || edits[0].Operation != Insert {
+		t.Errorf("wrong edits: %v", edits)
+	}
+	if edits := Lines([]string{"a"}, nil); len(edits) != 1 || edits[0].Operation != Delete {
+		t.Errorf("wrong edits: %v", edits)
+	}
+}
+
+func TestLinesShortestScript(t *testing.T) {
+	edits := Lines(strings.Split("abcabba", ""), strings.Split("cbabac", ""))
+
+	changes := 0
+	for _, edit := range edits {
+		if edit.Operation != Equal {
+			changes++
+		}
+	}
+	if changes != 5 {
+		t.Errorf("expected 5 changes but found %d: %v", changes, edits)
+	}
+}
+
+func TestUnifiedNoDifferences(t *testing.T) {
+	result := Unified("expected", "actual", []string{"a"}, []string{"a"}, 3)
+
+	if result != "" {
+		t.Error("wrong diff: " + result)
+	}
+}
+
+func TestUnified(t *testing.T) {
+	from := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+	to := []string{"1", "2", "three", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"}
+
+	expected := "--- expected\n" +
+		"+++ actual\n" +
+		"@@ -2,3 +2,3 @@\n" +
+		" 2\n" +
+		"-3\n" +
+		"+three\n" +
+		" 4\n" +
+		"@@ -12 +12,2 @@\n" +
+		" 12\n" +
+		"+13\n"
+
+	result := Unified("expected", "actual", from, to, 1)
+	if result != expected {
+		t.Error("wrong diff:\n" + result)
+	}
+}
+
+func TestUnifiedMergesCloseHunks(t *testing.T) {
+	from := []string{"1", "2", "3", "4", "5"}
+	to := []string{"one", "2", "3", "four", "5"}
+
+	expected := "--- a\n" +
+		"+++ b\n" +
+		"@@ -1,5 +1,5 @@\n" +
+		"-1\n" +
+		"+one\n" +
+		" 2\n" +
+		" 3\n" +
+		"-4\n" +
+		"+four\n" +
+		" 5\n"
+
+	result := Unified("a", "b", from, to, 1)
+	if result != expected {
+		t.Error("wrong diff:\n" + result)
+	}
+}
+
+func TestUnifiedEmptyRange(t *testing.T) {
+	result := Unified("a", "b", nil, []string{"x"}, 3)
+
+	if result != "--- a\n+++ b\n@@ -0,0 +1 @@\n+x\n" {
+		t.Error("wrong diff:\n" + result)
+	}
+}
+
+func TestLinesRebuildsBothSides(t *testing.T) {
+	from, to := differentDocuments(300)
+
+	var rebuiltFrom, rebuiltTo []string
+	for _, edit := range Lines(from, to) {
+		if edit.Operation != Insert {
+			rebuiltFrom = append(rebuiltFrom, edit.Line)
+		}
+		if edit.Operation != Delete {
+			rebuiltTo = append(rebuiltTo, edit.Line)
+		}
+	}
+
+	if strings.Join(rebuiltFrom, "\n") != strings.Join(from, "\n") {
+		t.Error("the edits do not contain the 'from' lines")
+	}
+	if strings.Join(rebuiltTo, "\n") != strings.Join(to, "\n") {
+		t.Error("the edits do not contain the 'to' lines")
+	}
+}
+
+func BenchmarkLinesVeryDifferent(b *testing.B) {
+	from, to := differentDocuments(5000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Lines(from, to)
+	}
+}
+
+// differentDocuments returns two documents where only every third line is the same.
+func differentDocuments(lines int) ([]string, []string) {
+	from := make([]string, lines)
+	to := make([]string, lines)
+	for i := range from {
+		from[i] = fmt.Sprintf("\"key%d\": %d,", i, i)
+		if i%3 == 0 {
+			to[i] = from[i]
+		} else {
+			to[i] = fmt.Sprintf("\"other%d\": %d,", i, -i)
+		}
+	}
+	return from, to
+}
@@ -0,0 +1,9 @@
+package matcher
+
+// Ignore is the special value that can be used in the expected JSON to validate that a field exists,
+// but skip the validation of its value.
+const Ignore = "@ignore@"
+
+func IsIgnore(value any) bool {
+	return value == Ignore
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"github.com/go-clarum/clarum-json/recorder"
+	"reflect"
+)
+
+// The functions below forward the expected & actual values to the configured recorder,
+// but only if it implements the optional [recorder.PairRecorder] interface.
+
+func RecordPairStart(rec recorder.Recorder, path string, fieldName string, kind reflect.Kind, message string) {
+	if pairRecorder, ok := rec.(recorder.PairRecorder); ok {
+		pairRecorder.AppendPairStart(path, fieldName, kind, message)
+	}
+}
+
+func RecordPairEnd(rec recorder.Recorder, path string, kind reflect.Kind) {
+	if pairRecorder, ok := rec.(recorder.PairRecorder); ok {
+		pairRecorder.AppendPairEnd(path, kind)
+	}
+}
+
+func RecordPair(rec recorder.Recorder, path string, fieldName string, expected any, actual any, message string) {
+	if pairRecorder, ok := rec.(recorder.PairRecorder); ok {
+		pairRecorder.AppendPair(path, fieldName, expected, actual, message)
+	}
+}
+
+func RecordMissingPair(rec recorder.Recorder, path string, fieldName string, expected any) {
+	if pairRecorder, ok := rec.(recorder.PairRecorder); ok {
+		pairRecorder.AppendMissingPair(path, fieldName, expected)
+	}
+}
+
+func RecordUnexpectedPair(rec recorder.Recorder, path string, fieldName string, actual any) {
+	if pairRecorder, ok := rec.(recorder.PairRecorder); ok {
+		pairRecorder.AppendUnexpectedPair(path, fieldName, actual)
+	}
+}
+
+// RecordTruncation forwards the end of a comparison that was stopped early, if the recorder implements
+// the optional [recorder.TruncationRecorder] interface.
+func RecordTruncation(rec recorder.Recorder, message string) {
+	if truncationRecorder, ok := rec.(recorder.TruncationRecorder); ok {
+		truncationRecorder.AppendTruncation(message)
+	}
+}
@@ -0,0 +1,254 @@
+package jsonpath
+
+import (
+	"reflect"
+	"regexp"
+	"unicode/utf8"
+)
+
+// logicalExpression is the condition of a filter selector, evaluated for each child of the filtered node.
+type logicalExpression interface {
+	test(current any, root any) bool
+}
+
+// expression is an operand of a comparison or the argument of a function.
+// It evaluates to a list of values: a single value, the values of the nodes selected by a query
+// or an empty list if there is no value ('Nothing' in RFC 9535).
+type expression interface {
+	evaluate(current any, root any) []any
+}
+
+type orExpression struct {
+	operands []logicalExpression
+}
+
+type andExpression struct {
+	operands []logicalExpression
+}
+
+type notExpression struct {
+	operand logicalExpression
+}
+
+type comparisonExpression struct {
+	left     expression
+	operator string
+	right    expression
+}
+
+// testExpression checks if a query selects at least one node or if a function returns true.
+type testExpression struct {
+	operand expression
+}
+
+type literal struct {
+	value any
+}
+
+type query struct {
+	relative bool
+	segments []segment
+}
+
+type functionExpression struct {
+	function  function
+	arguments []expression
+}
+
+func (expression orExpression) test(current any, root any) bool {
+	for _, operand := range expression.operands {
+		if operand.test(current, root) {
+			return true
+		}
+	}
+	return false
+}
+
+func (expression andExpression) test(current any, root any) bool {
+	for _, operand := range expression.operands {
+		if !operand.test(current, root) {
+			return false
+		}
+	}
+	return true
+}
+
+func (expression notExpression) test(current any, root any) bool {
+	return !expression.operand.test(current, root)
+}
+
+func (expression comparisonExpression) test(current any, root any) bool {
+	left := expression.left.evaluate(current, root)
+	right := expression.right.evaluate(current, root)
+
+	switch expression.operator {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	case "<":
+		return less(left, right)
+	case "<=":
+		return less(left, right) || equal(left, right)
+	case ">":
+		return less(right, left)
+	default:
+		return less(right, left) || equal(left, right)
+	}
+}
+
+func (expression testExpression) test(current any, root any) bool {
+	result := expression.operand.evaluate(current, root)
+
+	// the parser only allows logical functions as a test
+	if _, isFunction := expression.operand.(functionExpression); isFunction {
+		return len(result) == 1 && result[0] == true
+	}
+	return len(result) > 0
+}
+
+func (literal literal) evaluate(current any, root any) []any {
+	return []any{literal.value}
+}
+
+func (query query) evaluate(current any, root any) []any {
+	start := root
+	if query.relative {
+		start = current
+	}
+
+	nodes := []Node{{RootPath, RootPointer, start}}
+	for _, segment := range query.segments {
+		nodes = segment.apply(nodes, root)
+	}
+
+	values := make([]any, len(nodes))
+	for i, node := range nodes {
+		values[i] = node.Value
+	}
+	return values
+}
+
+func (expression functionExpression) evaluate(current any, root any) []any {
+	arguments := make([][]any, len(expression.arguments))
+	for i, argument := range expression.arguments {
+		arguments[i] = argument.evaluate(current, root)
+	}
+	return expression.function.evaluate(arguments)
+}
+
+// equal compares two values as defined by RFC 9535: two missing values are equal, numbers are compared by value
+// and arrays & objects are compared deeply.
+func equal(left []any, right []any) bool {
+	if len(left) != 1 || len(right) != 1 {
+		return len(left) == 0 && len(right) == 0
+	}
+	return reflect.DeepEqual(left[0], right[0])
+}
+
+// less is only defined for two numbers or two strings; any other comparison is false.
+func less(left []any, right []any) bool {
+	if len(left) != 1 || len(right) != 1 {
+		return false
+	}
+
+	switch leftValue := left[0].(type) {
+	case float64:
+		rightValue, ok := right[0].(float64)
+		return ok && leftValue < rightValue
+	case string:
+		rightValue, ok := right[0].(string)
+		return ok && leftValue < rightValue
+	default:
+		return false
+	}
+}
+
+type function struct {
+	arguments int
+	// logical functions can be used as a test expression, but not in a comparison
+	logical  bool
+	evaluate func(arguments [][]any) []any
+	// pattern converts the I-Regexp argument of the regular expression functions into a Go regular expression,
+	// so that a pattern literal can be compiled once when the filter is parsed
+	pattern func(pattern string) string
+}
+
+// functions available in filter expressions, as defined by RFC 9535.
+var functions = map[string]function{
+	"length": {1, false, functionLength, nil},
+	"count":  {1, false, functionCount, nil},
+	"match":  {2, true, functionMatch, matchPattern},
+	"search": {2, true, functionSearch, searchPattern},
+	"value":  {1, false, functionValue, nil},
+}
+
+func functionLength(arguments [][]any) []any {
+	if len(arguments[0]) != 1 {
+		return nil
+	}
+
+	switch value := arguments[0][0].(type) {
+	case string:
+		return []any{float64(utf8.RuneCountInString(value))}
+	case []any:
+		return []any{float64(len(value))}
+	case map[string]any:
+		return []any{float64(len(value))}
+	default:
+		return nil
+	}
+}
+
+func functionCount(arguments [][]any) []any {
+	return []any{float64(len(arguments[0]))}
+}
+
+func functionMatch(arguments [][]any) []any {
+	return regexpFunction(arguments, matchPattern)
+}
+
+func functionSearch(arguments [][]any) []any {
+	return regexpFunction(arguments, searchPattern)
+}
+
+func matchPattern(pattern string) string {
+	return "^(?:" + pattern + ")$"
+}
+
+func searchPattern(pattern string) string {
+	return pattern
+}
+
+// regexpFunction matches the value with the pattern, which was compiled by the parser if it is a literal.
+// Patterns selected by a query are compiled for each evaluation.
+func regexpFunction(arguments [][]any, pattern func(pattern string) string) []any {
+	if len(arguments[0]) != 1 || len(arguments[1]) != 1 {
+		return []any{false}
+	}
+
+	value, valueIsString := arguments[0][0].(string)
+	if !valueIsString {
+		return []any{false}
+	}
+
+	switch typedPattern := arguments[1][0].(type) {
+	case *regexp.Regexp:
+		return []any{typedPattern.MatchString(value)}
+	case string:
+		expression, err := regexp.Compile(pattern(typedPattern))
+		if err != nil {
+			return []any{false}
+		}
+		return []any{expression.MatchString(value)}
+	default:
+		return []any{false}
+	}
+}
+
+func functionValue(arguments [][]any) []any {
+	if len(arguments[0]) != 1 {
+		return nil
+	}
+	return arguments[0]
+}
@@ -0,0 +1,97 @@
+package jsonpath
+
+import (
+	"fmt"
+)
+
+// Path is a parsed JSONPath query (RFC 9535). It can be used to select nodes from any document decoded
+// with encoding/json into an 'any' value.
+//
+// Create a path with [Parse] and reuse it; a Path is immutable and goroutine safe.
+type Path struct {
+	expression string
+	segments   []segment
+}
+
+// Node is a value selected by a [Path], together with its location in the document.
+// The Path uses the same notation as the errors of the comparator.
+type Node struct {
+	Path    string
+	Pointer string
+	Value   any
+}
+
+// Parse parses a JSONPath query. The supported syntax is the one defined in RFC 9535:
+// names, wildcards, indexes, slices, filters with the standard functions (length, count, match, search & value)
+// and recursive descent.
+func Parse(expression string) (*Path, error) {
+	parser := &parser{input: expression}
+
+	segments, err := parser.parseQuery()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSONPath [%s] - %s", expression, err)
+	}
+
+	return &Path{expression, segments}, nil
+}
+
+// MustParse is like [Parse] but panics if the expression cannot be parsed.
+func MustParse(expression string) *Path {
+	path, err := Parse(expression)
+	if err != nil {
+		panic(err)
+	}
+	return path
+}
+
+// Select parses the expression and selects the matching nodes from the document.
+func Select(expression string, document any) ([]Node, error) {
+	path, err := Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	return path.Select(document), nil
+}
+
+// Select returns the nodes of the document matched by the path, in document order.
+// Object members are visited in the order of their sorted keys.
+func (path *Path) Select(document any) []Node {
+	nodes := []Node{{RootPath, RootPointer, document}}
+	for _, segment := range path.segments {
+		nodes = segment.apply(nodes, document)
+	}
+	return nodes
+}
+
+// IsSingular checks if the path can select at most one node, which is the case if it only contains
+// name & index selectors, without recursive descent.
+func (path *Path) IsSingular() bool {
+	return isSingular(path.segments)
+}
+
+// Pointer converts a singular path into a JSON Pointer (RFC 6901). This is not possible for paths that are not singular
+// or contain negative indexes, as these depend on the document.
+func (path *Path) Pointer() (string, bool) {
+	if !path.IsSingular() {
+		return "", false
+	}
+
+	pointer := RootPointer
+	for _, segment := range path.segments {
+		switch selector := segment.selectors[0].(type) {
+		case nameSelector:
+			pointer = GetObjectChildPointer(pointer, selector.name)
+		case indexSelector:
+			if selector.index < 0 {
+				return "", false
+			}
+			pointer = GetArrayIndexPointer(pointer, selector.index)
+		}
+	}
+	return pointer, true
+}
+
+func (path *Path) String() string {
+	return path.expression
+}
@@ -0,0 +1,213 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const storeJson = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+      {"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+    ],
+    "bicycle": {"color": "red", "price": 399}
+  }
+}`
+
+func TestSelectNames(t *testing.T) {
+	checkSelect(t, "$.store.bicycle.color", storeJson, []any{"red"})
+	checkSelect(t, "$['store']['bicycle'][\"color\"]", storeJson, []any{"red"})
+	checkSelect(t, "$.store.missing", storeJson, []any{})
+	checkSelect(t, "$", `{"a": 1}`, []any{map[string]any{"a": 1.0}})
+}
+
+func TestSelectWildcard(t *testing.T) {
+	checkSelect(t, "$.store.bicycle.*", storeJson, []any{"red", 399.0})
+	checkSelect(t, "$.store.book[*].author", storeJson,
+		[]any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"})
+}
+
+func TestSelectIndexes(t *testing.T) {
+	checkSelect(t, "$.store.book[2].title", storeJson, []any{"Moby Dick"})
+	checkSelect(t, "$.store.book[-1].title", storeJson, []any{"The Lord of the Rings"})
+	checkSelect(t, "$.store.book[4].title", storeJson, []any{})
+	checkSelect(t, "$.store.book[0, 1].price", storeJson, []any{8.95, 12.99})
+}
+
+func TestSelectSlices(t *testing.T) {
+	array := `["a", "b", "c", "d", "e", "f", "g"]`
+
+	checkSelect(t, "$[1:3]", array, []any{"b", "c"})
+	checkSelect(t, "$[5:]", array, []any{"f", "g"})
+	checkSelect(t, "$[1:5:2]", array, []any{"b", "d"})
+	checkSelect(t, "$[5:1:-2]", array, []any{"f", "d"})
+	checkSelect(t, "$[::-1]", array, []any{"g", "f", "e", "d", "c", "b", "a"})
+	checkSelect(t, "$[-2:]", array, []any{"f", "g"})
+	checkSelect(t, "$[::0]", array, []any{})
+	checkSelect(t, "$[ 1 : 2 ]", array, []any{"b"})
+}
+
+func TestSelectDescendants(t *testing.T) {
+	checkSelect(t, "$..author", storeJson,
+		[]any{"Nigel Rees", "Evelyn Waugh", "Herman Melville", "J. R. R. Tolkien"})
+	checkSelect(t, "$.store..price", storeJson, []any{399.0, 8.95, 12.99, 8.99, 22.99})
+	checkSelect(t, "$..book[2].title", storeJson, []any{"Moby Dick"})
+	checkSelect(t, "$..[0]", `{"a": [1, [2, 3]]}`, []any{1.0, 2.0})
+	checkSelect(t, "$..*", `{"a": {"b": 1}}`, []any{map[string]any{"b": 1.0}, 1.0})
+}
+
+func TestSelectFilters(t *testing.T) {
+	checkSelect(t, "$.store.book[?@.isbn].title", storeJson, []any{"Moby Dick", "The Lord of the Rings"})
+	checkSelect(t, "$.store.book[?!@.isbn].title", storeJson, []any{"Sayings of the Century", "Sword of Honour"})
+	checkSelect(t, "$.store.book[?@.price < 10].title", storeJson, []any{"Sayings of the Century", "Moby Dick"})
+	checkSelect(t, "$.store.book[?@.price >= 12.99 && @.category == 'fiction'].title", storeJson,
+		[]any{"Sword of Honour", "The Lord of the Rings"})
+	checkSelect(t, "$.store.book[?@.author == 'Nigel Rees' || @.price > 20].price", storeJson, []any{8.95, 22.99})
+	checkSelect(t, "$.store.book[?!(@.price < 10)].price", storeJson, []any{12.99, 22.99})
+	checkSelect(t, "$.store.book[?@.price > $.store.bicycle.price]", storeJson, []any{})
+	checkSelect(t, "$[?@.status=='active'].id",
+		`[{"id": 1, "status": "active"}, {"id": 2, "status": "inactive"}]`, []any{1.0})
+}
+
+func TestSelectFilterComparisons(t *testing.T) {
+	document := `[{"a": null}, {"a": [1, 2]}, {"a": {"b": true}}, {"b": 1}, {"a": "x"}]`
+
+	checkSelect(t, "$[?@.a == null].a", document, []any{nil})
+	checkSelect(t, "$[?@.a == @.c]", document, []any{map[string]any{"b": 1.0}})
+	checkSelect(t, "$[?@.a != 'x'].b", document, []any{1.0})
+	checkSelect(t, "$[?@.a > 'w'].a", document, []any{"x"})
+	checkSelect(t, "$[?@.a <= 1].a", document, []any{})
+}
+
+func TestSelectFilterFunctions(t *testing.T) {
+	document := `[{"name": "Bruce", "aliases": ["Batman"]}, {"name": "Dick", "aliases": ["Robin", "Nightwing"]}]`
+
+	checkSelect(t, "$[?length(@.aliases) == 2].name", document, []any{"Dick"})
+	checkSelect(t, "$[?length(@.name) > 4].name", document, []any{"Bruce"})
+	checkSelect(t, "$[?count(@.aliases[*]) == 1].name", document, []any{"Bruce"})
+	checkSelect(t, "$[?match(@.name, 'B.*')].name", document, []any{"Bruce"})
+	checkSelect(t, "$[?match(@.name, 'B')].name", document, []any{})
+	checkSelect(t, "$[?search(@.name, 'ic')].name", document, []any{"Dick"})
+	checkSelect(t, "$[?value(@.aliases[0]) == 'Robin'].name", document, []any{"Dick"})
+	checkSelect(t, "$[?match(@.name, $[0].name)].name", document, []any{"Bruce"})
+	checkSelect(t, "$[?match(@.name, '(')].name", document, []any{})
+}
+
+func TestSelectPaths(t *testing.T) {
+	var document any
+	_ = json.Unmarshal([]byte(`{"a.b": [{"c": 1}]}`), &document)
+
+	nodes, err := Select("$..c", document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Path != "$['a.b'][0].c" || nodes[0].Pointer != "/a.b/0/c" {
+		t.Errorf("wrong nodes: %v", nodes)
+	}
+}
+
+func TestIsSingular(t *testing.T) {
+	testCases := map[string]bool{
+		"$":           true,
+		"$.a[0]['b']": true,
+		"$.a[*]":      false,
+		"$..a":        false,
+		"$.a[0, 1]":   false,
+		"$.a[1:2]":    false,
+		"$[?@.a]":     false,
+	}
+
+	for expression, singular := range testCases {
+		if MustParse(expression).IsSingular() != singular {
+			t.Errorf("wrong result for [%s]", expression)
+		}
+	}
+}
+
+func TestPointer(t *testing.T) {
+	pointer, ok := MustParse("$.a['b/c'][2]").Pointer()
+	if !ok || pointer != "/a/b~1c/2" {
+		t.Errorf("wrong pointer: %s", pointer)
+	}
+
+	if _, ok := MustParse("$.a[-1]").Pointer(); ok {
+		t.Error("negative indexes have no pointer")
+	}
+	if _, ok := MustParse("$.a[*]").Pointer(); ok {
+		t.Error("paths that are not singular have no pointer")
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	checkSelect(t, `$['it\'s']`, `{"it's": 1}`, []any{1.0})
+	checkSelect(t, `$["quote\"d"]`, `{"quote\"d": 1}`, []any{1.0})
+	checkSelect(t, `$['é😀']`, `{"é😀": 1}`, []any{1.0})
+	checkSelect(t, `$['tab\t']`, `{"tab\t": 1}`, []any{1.0})
+}
+
+func TestParseErrors(t *testing.T) {
+	invalidExpressions := []string{
+		"",
+		"a.b",
+		"$.",
+		"$.a.",
+		"$[",
+		"$[1",
+		"$['a'",
+		"$['a\\x']",
+		"$[01]",
+		"$[-0]",
+		"$[1.5]",
+		"$[9007199254740992]",
+		"$[?@.a == 1 ",
+		"$[?1]",
+		"$[?@.a = 1]",
+		"$[?@.* == 1]",
+		"$[?unknown(@.a)]",
+		"$[?length(@.a, @.b)]",
+		"$[?match(@.a, 'x') == true]",
+		"$[?length(@.a)]",
+		"$[?!count(@.*)]",
+		"$[?!@.a == 1]",
+		"$.a b",
+	}
+
+	for _, expression := range invalidExpressions {
+		if _, err := Parse(expression); err == nil {
+			t.Errorf("expression should be invalid: [%s]", expression)
+		}
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	_, err := Parse("$.a[")
+
+	if err == nil || err.Error() != "invalid JSONPath [$.a[] - invalid selector at position 4" {
+		t.Errorf("wrong error: %v", err)
+	}
+}
+
+func checkSelect(t *testing.T, expression string, rawJson string, expectedValues []any) {
+	var document any
+	if err := json.Unmarshal([]byte(rawJson), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Parse(expression)
+	if err != nil {
+		t.Errorf("unable to parse [%s]: %s", expression, err)
+		return
+	}
+
+	values := []any{}
+	for _, node := range path.Select(document) {
+		values = append(values, node.Value)
+	}
+	if !reflect.DeepEqual(values, expectedValues) {
+		t.Errorf("wrong values for [%s]: %v", expression, values)
+	}
+}
@@ -0,0 +1,559 @@
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The JSON numbers that can be represented exactly, the only valid range for indexes & slices.
+const maxSafeInteger = 1<<53 - 1
+
+// parser is a recursive descent parser following the ABNF grammar of RFC 9535.
+type parser struct {
+	input    string
+	position int
+}
+
+func (parser *parser) parseQuery() ([]segment, error) {
+	if !parser.consume("$") {
+		return nil, parser.errorf("query must start with '$'")
+	}
+
+	segments, err := parser.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+	if !parser.done() {
+		return nil, parser.errorf("unexpected character '%c'", parser.peek())
+	}
+	return segments, nil
+}
+
+// parseSegments parses segments as long as they follow, allowing blanks between them.
+func (parser *parser) parseSegments() ([]segment, error) {
+	var segments []segment
+
+	for {
+		start := parser.position
+		parser.skipBlanks()
+
+		if parser.consume("..") {
+			selectors, err := parser.parseShorthandOrBrackets(true)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment{true, selectors})
+		} else if parser.consume(".") {
+			selectors, err := parser.parseShorthandOrBrackets(false)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment{false, selectors})
+		} else if parser.peek() == '[' {
+			selectors, err := parser.parseBracketedSelection()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment{false, selectors})
+		} else {
+			parser.position = start
+			return segments, nil
+		}
+	}
+}
+
+// parseShorthandOrBrackets parses what follows a '.' or '..': a wildcard, a member name or,
+// for descendant segments only, a bracketed selection.
+func (parser *parser) parseShorthandOrBrackets(descendant bool) ([]selector, error) {
+	if parser.consume("*") {
+		return []selector{wildcardSelector{}}, nil
+	}
+	if descendant && parser.peek() == '[' {
+		return parser.parseBracketedSelection()
+	}
+
+	name := parser.parseMemberName()
+	if name == "" {
+		return nil, parser.errorf("expected a member name")
+	}
+	return []selector{nameSelector{name}}, nil
+}
+
+func (parser *parser) parseMemberName() string {
+	start := parser.position
+	for !parser.done() {
+		char, size := utf8.DecodeRuneInString(parser.input[parser.position:])
+		isFirst := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || char == '_' || char > 0x7F
+		isDigit := char >= '0' && char <= '9'
+		if !isFirst && (parser.position == start || !isDigit) {
+			break
+		}
+		parser.position += size
+	}
+	return parser.input[start:parser.position]
+}
+
+func (parser *parser) parseBracketedSelection() ([]selector, error) {
+	parser.consume("[")
+	var selectors []selector
+
+	for {
+		parser.skipBlanks()
+		selector, err := parser.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+
+		parser.skipBlanks()
+		if parser.consume("]") {
+			return selectors, nil
+		}
+		if !parser.consume(",") {
+			return nil, parser.errorf("expected ',' or ']'")
+		}
+	}
+}
+
+func (parser *parser) parseSelector() (selector, error) {
+	switch char := parser.peek(); {
+	case char == '\'' || char == '"':
+		name, err := parser.parseString()
+		return nameSelector{name}, err
+	case char == '*':
+		parser.position++
+		return wildcardSelector{}, nil
+	case char == '?':
+		parser.position++
+		parser.skipBlanks()
+		expression, err := parser.parseLogicalOr()
+		return filterSelector{expression}, err
+	case char == ':' || char == '-' || isDigit(char):
+		return parser.parseIndexOrSlice()
+	default:
+		return nil, parser.errorf("invalid selector")
+	}
+}
+
+func (parser *parser) parseIndexOrSlice() (selector, error) {
+	var bounds [3]*int
+
+	for part := 0; part < 3; part++ {
+		parser.skipBlanks()
+		if char := parser.peek(); char == '-' || isDigit(char) {
+			value, err := parser.parseInteger()
+			if err != nil {
+				return nil, err
+			}
+			bounds[part] = &value
+		}
+
+		parser.skipBlanks()
+		if part == 2 || !parser.consume(":") {
+			if part == 0 {
+				if bounds[0] == nil {
+					return nil, parser.errorf("expected an index")
+				}
+				return indexSelector{*bounds[0]}, nil
+			}
+			break
+		}
+	}
+
+	return sliceSelector{bounds[0], bounds[1], bounds[2]}, nil
+}
+
+func (parser *parser) parseInteger() (int, error) {
+	start := parser.position
+	parser.consume("-")
+	digitsStart := parser.position
+	for isDigit(parser.peek()) {
+		parser.position++
+	}
+
+	text := parser.input[start:parser.position]
+	digits := parser.input[digitsStart:parser.position]
+	if digits == "" || (len(digits) > 1 && digits[0] == '0') || text == "-0" {
+		return 0, parser.errorf("invalid integer [%s]", text)
+	}
+
+	value, err := strconv.Atoi(text)
+	if err != nil || value > maxSafeInteger || value < -maxSafeInteger {
+		return 0, parser.errorf("integer out of range [%s]", text)
+	}
+	return value, nil
+}
+
+func (parser *parser) parseLogicalOr() (logicalExpression, error) {
+	var operands []logicalExpression
+
+	for {
+		operand, err := parser.parseLogicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+
+		parser.skipBlanks()
+		if !parser.consume("||") {
+			break
+		}
+		parser.skipBlanks()
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return orExpression{operands}, nil
+}
+
+func (parser *parser) parseLogicalAnd() (logicalExpression, error) {
+	var operands []logicalExpression
+
+	for {
+		operand, err := parser.parseBasicExpression()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+
+		parser.skipBlanks()
+		if !parser.consume("&&") {
+			break
+		}
+		parser.skipBlanks()
+	}
+
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return andExpression{operands}, nil
+}
+
+// parseBasicExpression parses a negation, an expression in parentheses, a comparison or a test expression.
+func (parser *parser) parseBasicExpression() (logicalExpression, error) {
+	if parser.peek() == '!' && !strings.HasPrefix(parser.input[parser.position:], "!=") {
+		parser.position++
+		parser.skipBlanks()
+
+		// only expressions in parentheses & test expressions can be negated
+		var operand logicalExpression
+		var err error
+		if parser.peek() == '(' {
+			operand, err = parser.parseBasicExpression()
+		} else {
+			var testOperand expression
+			if testOperand, err = parser.parseOperand(); err == nil {
+				operand, err = parser.testExpression(testOperand)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return notExpression{operand}, nil
+	}
+
+	if parser.consume("(") {
+		parser.skipBlanks()
+		expression, err := parser.parseLogicalOr()
+		if err != nil {
+			return nil, err
+		}
+		parser.skipBlanks()
+		if !parser.consume(")") {
+			return nil, parser.errorf("expected ')'")
+		}
+		return expression, nil
+	}
+
+	left, err := parser.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	start := parser.position
+	parser.skipBlanks()
+	operator := parser.parseComparisonOperator()
+	if operator == "" {
+		parser.position = start
+		return parser.testExpression(left)
+	}
+
+	parser.skipBlanks()
+	right, err := parser.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parser.checkComparable(left); err != nil {
+		return nil, err
+	}
+	if err := parser.checkComparable(right); err != nil {
+		return nil, err
+	}
+	return comparisonExpression{left, operator, right}, nil
+}
+
+func (parser *parser) parseComparisonOperator() string {
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if parser.consume(operator) {
+			return operator
+		}
+	}
+	return ""
+}
+
+func (parser *parser) testExpression(operand expression) (logicalExpression, error) {
+	switch typedOperand := operand.(type) {
+	case literal:
+		return nil, parser.errorf("a literal must be compared")
+	case functionExpression:
+		if !typedOperand.function.logical {
+			return nil, parser.errorf("the result of a function that is not logical must be compared")
+		}
+	}
+	return testExpression{operand}, nil
+}
+
+// checkComparable makes sure that a comparison operand is a single value: queries must be singular
+// and functions must not return a logical value.
+func (parser *parser) checkComparable(operand expression) error {
+	switch typedOperand := operand.(type) {
+	case query:
+		if !isSingular(typedOperand.segments) {
+			return parser.errorf("only singular queries can be compared")
+		}
+	case functionExpression:
+		if typedOperand.function.logical {
+			return parser.errorf("the result of a logical function cannot be compared")
+		}
+	}
+	return nil
+}
+
+// parseOperand parses a literal, a query or a function call.
+func (parser *parser) parseOperand() (expression, error) {
+	switch char := parser.peek(); {
+	case char == '@' || char == '$':
+		parser.position++
+		segments, err := parser.parseSegments()
+		return query{char == '@', segments}, err
+	case char == '\'' || char == '"':
+		value, err := parser.parseString()
+		return literal{value}, err
+	case char == '-' || isDigit(char):
+		return parser.parseNumber()
+	case char >= 'a' && char <= 'z':
+		return parser.parseKeywordOrFunction()
+	default:
+		return nil, parser.errorf("invalid expression")
+	}
+}
+
+func (parser *parser) parseKeywordOrFunction() (expression, error) {
+	start := parser.position
+	for char := parser.peek(); (char >= 'a' && char <= 'z') || isDigit(char) || char == '_'; char = parser.peek() {
+		parser.position++
+	}
+	name := parser.input[start:parser.position]
+
+	if parser.peek() != '(' {
+		switch name {
+		case "true":
+			return literal{true}, nil
+		case "false":
+			return literal{false}, nil
+		case "null":
+			return literal{nil}, nil
+		default:
+			return nil, parser.errorf("unknown literal [%s]", name)
+		}
+	}
+
+	function, exists := functions[name]
+	if !exists {
+		return nil, parser.errorf("unknown function [%s]", name)
+	}
+	parser.position++
+
+	var arguments []expression
+	parser.skipBlanks()
+	for !parser.consume(")") {
+		if len(arguments) > 0 && !parser.consume(",") {
+			return nil, parser.errorf("expected ',' or ')'")
+		}
+		parser.skipBlanks()
+
+		argument, err := parser.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+		parser.skipBlanks()
+	}
+
+	if len(arguments) != function.arguments {
+		return nil, parser.errorf("function [%s] expects %d argument(s)", name, function.arguments)
+	}
+
+	// an invalid pattern is kept as it is, it never matches
+	if pattern, isLiteral := arguments[len(arguments)-1].(literal); isLiteral && function.pattern != nil {
+		if text, isString := pattern.value.(string); isString {
+			if expression, err := regexp.Compile(function.pattern(text)); err == nil {
+				arguments[len(arguments)-1] = literal{expression}
+			}
+		}
+	}
+	return functionExpression{function, arguments}, nil
+}
+
+func (parser *parser) parseNumber() (expression, error) {
+	start := parser.position
+	for char := parser.peek(); isDigit(char) || strings.ContainsRune("-+.eE", rune(char)); char = parser.peek() {
+		parser.position++
+	}
+
+	text := parser.input[start:parser.position]
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil || strings.HasPrefix(strings.TrimPrefix(text, "-"), ".") || strings.HasSuffix(text, ".") {
+		return nil, parser.errorf("invalid number [%s]", text)
+	}
+	return literal{value}, nil
+}
+
+// parseString parses a string literal in single or double quotes, with the JSON escape sequences.
+func (parser *parser) parseString() (string, error) {
+	quote := parser.peek()
+	parser.position++
+
+	var result strings.Builder
+	for {
+		if parser.done() {
+			return "", parser.errorf("unterminated string")
+		}
+
+		char, size := utf8.DecodeRuneInString(parser.input[parser.position:])
+		parser.position += size
+
+		switch {
+		case char == rune(quote):
+			return result.String(), nil
+		case char < 0x20:
+			return "", parser.errorf("invalid character in string")
+		case char == '\\':
+			escaped, err := parser.parseEscape(quote)
+			if err != nil {
+				return "", err
+			}
+			result.WriteRune(escaped)
+		default:
+			result.WriteRune(char)
+		}
+	}
+}
+
+func (parser *parser) parseEscape(quote byte) (rune, error) {
+	if parser.done() {
+		return 0, parser.errorf("unterminated string")
+	}
+
+	char := parser.input[parser.position]
+	parser.position++
+
+	switch char {
+	case 'b':
+		return '\b', nil
+	case 'f':
+		return '\f', nil
+	case 'n':
+		return '\n', nil
+	case 'r':
+		return '\r', nil
+	case 't':
+		return '\t', nil
+	case '/', '\\':
+		return rune(char), nil
+	case 'u':
+		return parser.parseUnicodeEscape()
+	default:
+		if char == quote {
+			return rune(char), nil
+		}
+		return 0, parser.errorf("invalid escape sequence '\\%c'", char)
+	}
+}
+
+func (parser *parser) parseUnicodeEscape() (rune, error) {
+	first, err := parser.parseHex()
+	if err != nil {
+		return 0, err
+	}
+	if !utf16.IsSurrogate(first) {
+		return first, nil
+	}
+
+	if !parser.consume("\\u") {
+		return 0, parser.errorf("invalid surrogate pair")
+	}
+	second, err := parser.parseHex()
+	if err != nil {
+		return 0, err
+	}
+
+	decoded := utf16.DecodeRune(first, second)
+	if decoded == utf8.RuneError {
+		return 0, parser.errorf("invalid surrogate pair")
+	}
+	return decoded, nil
+}
+
+func (parser *parser) parseHex() (rune, error) {
+	if parser.position+4 > len(parser.input) {
+		return 0, parser.errorf("invalid unicode escape")
+	}
+
+	value, err := strconv.ParseUint(parser.input[parser.position:parser.position+4], 16, 32)
+	if err != nil {
+		return 0, parser.errorf("invalid unicode escape")
+	}
+	parser.position += 4
+	return rune(value), nil
+}
+
+func (parser *parser) skipBlanks() {
+	for char := parser.peek(); char == ' ' || char == '\t' || char == '\n' || char == '\r'; char = parser.peek() {
+		parser.position++
+	}
+}
+
+func (parser *parser) consume(token string) bool {
+	if strings.HasPrefix(parser.input[parser.position:], token) {
+		parser.position += len(token)
+		return true
+	}
+	return false
+}
+
+func (parser *parser) peek() byte {
+	if parser.done() {
+		return 0
+	}
+	return parser.input[parser.position]
+}
+
+func (parser *parser) done() bool {
+	return parser.position >= len(parser.input)
+}
+
+func (parser *parser) errorf(format string, a ...any) error {
+	return errors.New(fmt.Sprintf("%s at position %d", fmt.Sprintf(format, a...), parser.position))
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
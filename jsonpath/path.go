@@ -0,0 +1,90 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strings"
+)
+
+const RootPath = "$"
+
+// nameEscaper escapes member names like the normalized paths of RFC 9535 (section 2.7), so that the paths
+// can be parsed again.
+var nameEscaper = newNameEscaper()
+
+func newNameEscaper() *strings.Replacer {
+	replacements := []string{"\\", "\\\\", "'", "\\'", "\b", "\\b", "\f", "\\f", "\n", "\\n", "\r", "\\r",
+		"\t", "\\t"}
+	for char := rune(0); char < 0x20; char++ {
+		if !strings.ContainsRune("\b\f\n\r\t", char) {
+			replacements = append(replacements, string(char), fmt.Sprintf("\\u%04x", char))
+		}
+	}
+	return strings.NewReplacer(replacements...)
+}
+
+// GetObjectChildPath uses the dot notation if the key is a valid shorthand member name (RFC 9535)
+// and the bracket notation otherwise, so keys containing dots, brackets, spaces or quotes remain unambiguous.
+func GetObjectChildPath(pathParent string, key string) string {
+	if isShorthandName(key) {
+		return fmt.Sprintf("%s.%s", pathParent, key)
+	}
+	return fmt.Sprintf("%s['%s']", pathParent, nameEscaper.Replace(key))
+}
+
+func GetArrayIndexPath(pathParent string, index int) string {
+	return fmt.Sprintf("%s[%d]", pathParent, index)
+}
+
+func IsRoot(path string) bool {
+	if path == RootPath {
+		return true
+	} else {
+		return false
+	}
+}
+
+// IsChildOfArray checks if the path ends with an array index, like '[3]'.
+func IsChildOfArray(path string) bool {
+	if !strings.HasSuffix(path, "]") {
+		return false
+	}
+
+	indexStart := strings.LastIndex(path, "[")
+	if indexStart < 0 || indexStart == len(path)-2 {
+		return false
+	}
+	for _, char := range path[indexStart+1 : len(path)-1] {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isShorthandName(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, char := range key {
+		isLetter := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || char == '_' || char > 0x7F
+		isDigit := char >= '0' && char <= '9'
+		if !isLetter && (i == 0 || !isDigit) {
+			return false
+		}
+	}
+	return true
+}
+
+// RootPointer is the JSON Pointer (RFC 6901) to the whole document.
+const RootPointer = ""
+
+var pointerTokenEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func GetObjectChildPointer(pointerParent string, key string) string {
+	return fmt.Sprintf("%s/%s", pointerParent, pointerTokenEscaper.Replace(key))
+}
+
+func GetArrayIndexPointer(pointerParent string, index int) string {
+	return fmt.Sprintf("%s/%d", pointerParent, index)
+}
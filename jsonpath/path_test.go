@@ -0,0 +1,123 @@
+package jsonpath
+
+import (
+	"testing"
+)
+
+func TestGetObjectChildPath(t *testing.T) {
+	result := GetObjectChildPath("object", "field")
+
+	if result != "object.field" {
+		t.Error("wrong path: " + result)
+	}
+}
+
+func TestGetArrayIndexPath(t *testing.T) {
+	result := GetArrayIndexPath("myarray", 1)
+
+	if result != "myarray[1]" {
+		t.Error("wrong path: " + result)
+	}
+}
+
+func TestIsRoot(t *testing.T) {
+	trueResult := IsRoot(RootPath)
+
+	if !trueResult {
+		t.Error("should be root")
+	}
+
+	falseResult := IsRoot("some.thing")
+
+	if falseResult {
+		t.Error("should not be root")
+	}
+}
+
+func TestIsChildOfArray(t *testing.T) {
+	trueResult := IsChildOfArray("myarray[3]")
+
+	if !trueResult {
+		t.Error("should be true")
+	}
+
+	falseResult := IsChildOfArray("myarray.")
+
+	if falseResult {
+		t.Error("should not false")
+	}
+}
+
+func TestGetObjectChildPointer(t *testing.T) {
+	result := GetObjectChildPointer("/object", "field")
+
+	if result != "/object/field" {
+		t.Error("wrong pointer: " + result)
+	}
+}
+
+func TestGetObjectChildPointerEscaping(t *testing.T) {
+	result := GetObjectChildPointer(RootPointer, "a/b~c")
+
+	if result != "/a~1b~0c" {
+		t.Error("wrong pointer: " + result)
+	}
+}
+
+func TestGetArrayIndexPointer(t *testing.T) {
+	result := GetArrayIndexPointer("/myarray", 1)
+
+	if result != "/myarray/1" {
+		t.Error("wrong pointer: " + result)
+	}
+}
+
+func TestGetObjectChildPathEscaping(t *testing.T) {
+	testCases := map[string]string{
+		"a.b":         "$['a.b']",
+		"items[0]":    "$['items[0]']",
+		"first name":  "$['first name']",
+		"it's":        "$['it\\'s']",
+		"back\\slash": "$['back\\\\slash']",
+		"line\nbreak": "$['line\\nbreak']",
+		"nul\x00":     "$['nul\\u0000']",
+		"1st":         "$['1st']",
+		"":            "$['']",
+		"field_1":     "$.field_1",
+		"straße":      "$.straße",
+	}
+
+	for key, expectedPath := range testCases {
+		if result := GetObjectChildPath(RootPath, key); result != expectedPath {
+			t.Errorf("wrong path for key [%s]: %s", key, result)
+		}
+	}
+}
+
+func TestGetObjectChildPathParse(t *testing.T) {
+	for _, key := range []string{"a.b", "it's", "back\\slash", "tab\there", "\b\f\n\r", "\x00\x1f", "\"quoted\"", "straße"} {
+		path := GetObjectChildPath(RootPath, key)
+
+		parsed, err := Parse(path)
+		if err != nil {
+			t.Errorf("unable to parse path [%s]: %v", path, err)
+			continue
+		}
+		nodes := parsed.Select(map[string]any{key: true})
+		if len(nodes) != 1 || nodes[0].Path != path {
+			t.Errorf("wrong nodes for path [%s]: %v", path, nodes)
+		}
+	}
+}
+
+func TestIsChildOfArrayEscapedName(t *testing.T) {
+	if IsChildOfArray("$['a[1]']") {
+		t.Error("should not be child of array")
+	}
+	if IsChildOfArray("$['a']") {
+		t.Error("should not be child of array")
+	}
+	if !IsChildOfArray("$['a'][12]") {
+		t.Error("should be child of array")
+	}
+}
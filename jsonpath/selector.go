@@ -0,0 +1,213 @@
+package jsonpath
+
+import (
+	"sort"
+)
+
+// segment is a child segment ('.name', '[...]') or, if descendant is set, a descendant segment ('..name', '..[...]').
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+type selector interface {
+	// selectNodes appends the children of the node matched by this selector to the result.
+	selectNodes(node Node, root any, result []Node) []Node
+	singular() bool
+}
+
+type nameSelector struct {
+	name string
+}
+
+type wildcardSelector struct {
+}
+
+type indexSelector struct {
+	index int
+}
+
+type sliceSelector struct {
+	start *int
+	end   *int
+	step  *int
+}
+
+type filterSelector struct {
+	expression logicalExpression
+}
+
+func (segment segment) apply(nodes []Node, root any) []Node {
+	var result []Node
+
+	for _, node := range nodes {
+		if segment.descendant {
+			result = segment.applyDescendants(node, root, result)
+		} else {
+			for _, selector := range segment.selectors {
+				result = selector.selectNodes(node, root, result)
+			}
+		}
+	}
+	return result
+}
+
+// applyDescendants applies the selectors to the node and then to all of its descendants, in document order.
+func (segment segment) applyDescendants(node Node, root any, result []Node) []Node {
+	for _, selector := range segment.selectors {
+		result = selector.selectNodes(node, root, result)
+	}
+	for _, child := range children(node) {
+		result = segment.applyDescendants(child, root, result)
+	}
+	return result
+}
+
+func (selector nameSelector) selectNodes(node Node, root any, result []Node) []Node {
+	if object, ok := node.Value.(map[string]any); ok {
+		if value, exists := object[selector.name]; exists {
+			result = append(result, objectChild(node, selector.name, value))
+		}
+	}
+	return result
+}
+
+func (selector wildcardSelector) selectNodes(node Node, root any, result []Node) []Node {
+	return append(result, children(node)...)
+}
+
+func (selector indexSelector) selectNodes(node Node, root any, result []Node) []Node {
+	if array, ok := node.Value.([]any); ok {
+		index := selector.index
+		if index < 0 {
+			index += len(array)
+		}
+		if index >= 0 && index < len(array) {
+			result = append(result, arrayChild(node, index, array[index]))
+		}
+	}
+	return result
+}
+
+// selectNodes follows the slice algorithm of RFC 9535: negative bounds count from the end of the array,
+// a negative step iterates backwards and a step of 0 selects nothing.
+func (selector sliceSelector) selectNodes(node Node, root any, result []Node) []Node {
+	array, ok := node.Value.([]any)
+	if !ok {
+		return result
+	}
+
+	length := len(array)
+	step := 1
+	if selector.step != nil {
+		step = *selector.step
+	}
+	if step == 0 {
+		return result
+	}
+
+	start, end := 0, length
+	if step < 0 {
+		start, end = length-1, -length-1
+	}
+	if selector.start != nil {
+		start = normalizeIndex(*selector.start, length)
+	}
+	if selector.end != nil {
+		end = normalizeIndex(*selector.end, length)
+	}
+
+	if step > 0 {
+		lower := min(max(start, 0), length)
+		upper := min(max(end, 0), length)
+		for i := lower; i < upper; i += step {
+			result = append(result, arrayChild(node, i, array[i]))
+		}
+	} else {
+		upper := min(max(start, -1), length-1)
+		lower := min(max(end, -1), length-1)
+		for i := upper; lower < i; i += step {
+			result = append(result, arrayChild(node, i, array[i]))
+		}
+	}
+	return result
+}
+
+func (selector filterSelector) selectNodes(node Node, root any, result []Node) []Node {
+	for _, child := range children(node) {
+		if selector.expression.test(child.Value, root) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+func (selector nameSelector) singular() bool {
+	return true
+}
+
+func (selector wildcardSelector) singular() bool {
+	return false
+}
+
+func (selector indexSelector) singular() bool {
+	return true
+}
+
+func (selector sliceSelector) singular() bool {
+	return false
+}
+
+func (selector filterSelector) singular() bool {
+	return false
+}
+
+func isSingular(segments []segment) bool {
+	for _, segment := range segments {
+		if segment.descendant || len(segment.selectors) != 1 || !segment.selectors[0].singular() {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeIndex(index int, length int) int {
+	if index < 0 {
+		return index + length
+	}
+	return index
+}
+
+// children returns the items of an array or the members of an object, sorted by their key.
+func children(node Node) []Node {
+	switch value := node.Value.(type) {
+	case []any:
+		result := make([]Node, len(value))
+		for i, item := range value {
+			result[i] = arrayChild(node, i, item)
+		}
+		return result
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		result := make([]Node, len(keys))
+		for i, key := range keys {
+			result[i] = objectChild(node, key, value[key])
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func objectChild(parent Node, key string, value any) Node {
+	return Node{GetObjectChildPath(parent.Path, key), GetObjectChildPointer(parent.Pointer, key), value}
+}
+
+func arrayChild(parent Node, index int, value any) Node {
+	return Node{GetArrayIndexPath(parent.Path, index), GetArrayIndexPointer(parent.Pointer, index), value}
+}
@@ -2,7 +2,7 @@ package recorder
 
 import (
 	"fmt"
-	"github.com/go-clarum/clarum-json/internal/path"
+	"github.com/go-clarum/clarum-json/jsonpath"
 	"reflect"
 	"strings"
 )
@@ -21,13 +21,17 @@ func (recorder *DefaultRecorder) GetLog() string {
 	return recorder.logResult.String()
 }
 
+func (recorder *DefaultRecorder) Reset() {
+	recorder.logResult.Reset()
+}
+
 func (recorder *DefaultRecorder) AppendFieldName(indent string, fieldName string) Recorder {
 	recorder.logResult.WriteString(fmt.Sprintf("%s\"%s\": ", indent, fieldName))
 	return recorder
 }
 
 func (recorder *DefaultRecorder) AppendIgnoreField(indent string, jsonPath string) Recorder {
-	childOfArray := path.IsChildOfArray(jsonPath)
+	childOfArray := jsonpath.IsChildOfArray(jsonPath)
 
 	if childOfArray {
 		recorder.logResult.WriteString(fmt.Sprintf("%s <-- ignoring field\n", indent))
@@ -39,7 +43,7 @@ func (recorder *DefaultRecorder) AppendIgnoreField(indent string, jsonPath strin
 }
 
 func (recorder *DefaultRecorder) AppendValue(indent string, jsonPath string, value any, kind reflect.Kind) Recorder {
-	childOfArray := path.IsChildOfArray(jsonPath)
+	childOfArray := jsonpath.IsChildOfArray(jsonPath)
 
 	var indentToSet string
 	if childOfArray {
@@ -69,7 +73,7 @@ func (recorder *DefaultRecorder) AppendMissingFieldErrorSignal(indent string, pa
 }
 
 func (recorder *DefaultRecorder) AppendStartObject(indent string, jsonPath string) Recorder {
-	childOfArray := path.IsChildOfArray(jsonPath)
+	childOfArray := jsonpath.IsChildOfArray(jsonPath)
 
 	if childOfArray {
 		recorder.logResult.WriteString(fmt.Sprintf("%s{", indent))
@@ -80,7 +84,7 @@ func (recorder *DefaultRecorder) AppendStartObject(indent string, jsonPath strin
 }
 
 func (recorder *DefaultRecorder) AppendEndObject(indent string, jsonPath string) Recorder {
-	root := path.IsRoot(jsonPath)
+	root := jsonpath.IsRoot(jsonPath)
 
 	if root {
 		recorder.logResult.WriteString(fmt.Sprintf("%s}\n", ""))
@@ -91,7 +95,7 @@ func (recorder *DefaultRecorder) AppendEndObject(indent string, jsonPath string)
 }
 
 func (recorder *DefaultRecorder) AppendStartArray(indent string, jsonPath string) Recorder {
-	childOfArray := path.IsChildOfArray(jsonPath)
+	childOfArray := jsonpath.IsChildOfArray(jsonPath)
 
 	if childOfArray {
 		recorder.logResult.WriteString(fmt.Sprintf("%s[", indent))
@@ -102,7 +106,7 @@ func (recorder *DefaultRecorder) AppendStartArray(indent string, jsonPath string
 }
 
 func (recorder *DefaultRecorder) AppendEndArray(indent string, jsonPath string) Recorder {
-	root := path.IsRoot(jsonPath)
+	root := jsonpath.IsRoot(jsonPath)
 
 	if root {
 		recorder.logResult.WriteString(fmt.Sprintf("%s]\n", indent))
@@ -116,3 +120,13 @@ func (recorder *DefaultRecorder) AppendNewLine() Recorder {
 	recorder.logResult.WriteString("\n")
 	return recorder
 }
+
+func (recorder *DefaultRecorder) AppendTruncation(message string) Recorder {
+	recorder.logResult.WriteString(formatTruncation(message))
+	return recorder
+}
+
+// formatTruncation returns the line that marks the end of a comparison that was stopped early.
+func formatTruncation(message string) string {
+	return fmt.Sprintf("... <-- %s\n", message)
+}
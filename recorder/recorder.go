@@ -20,3 +20,33 @@ type Recorder interface {
 	AppendNewLine() Recorder
 	GetLog() string
 }
+
+// PairRecorder is an optional extension of the [Recorder]. If the configured recorder implements it,
+// the [Comparator] additionally reports every node it visits together with both the expected and the actual value.
+// This allows output formats that show both documents, while following the same traversal as the validation.
+//
+// The fieldName is empty for array items and for the root. The message is empty if the node matches.
+type PairRecorder interface {
+	Recorder
+	AppendPairStart(path string, fieldName string, kind reflect.Kind, message string) Recorder
+	AppendPairEnd(path string, kind reflect.Kind) Recorder
+	AppendPair(path string, fieldName string, expected any, actual any, message string) Recorder
+	AppendMissingPair(path string, fieldName string, expected any) Recorder
+	AppendUnexpectedPair(path string, fieldName string, actual any) Recorder
+}
+
+// TruncationRecorder is an optional extension of the [Recorder]. If the configured recorder implements it,
+// the [Comparator] reports when it stopped the comparison before checking all values, so that the output
+// can be marked as incomplete.
+type TruncationRecorder interface {
+	Recorder
+	AppendTruncation(message string) Recorder
+}
+
+// ResettableRecorder is an optional extension of the [Recorder]. If the configured recorder implements it,
+// the [Comparator] clears the output of previous comparisons where only the output of the last one is relevant,
+// like in CompareEventually.
+type ResettableRecorder interface {
+	Recorder
+	Reset()
+}
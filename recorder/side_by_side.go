@@ -0,0 +1,227 @@
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	sameLineMarker     = " "
+	changedLineMarker  = "|"
+	expectedLineMarker = "<"
+	actualLineMarker   = ">"
+)
+
+// SideBySideRecorder prints the expected and the actual JSON in two aligned columns, expected on the left.
+// The rows follow the comparison, so matching fields are always printed next to each other.
+// The column separator marks the rows that differ, like 'diff --side-by-side' does:
+// - '|' the values differ
+// - '<' the value exists only in the expected JSON
+// - '>' the value exists only in the actual JSON
+//
+// The output is created when calling GetLog. As this implementation keeps its state in a slice, it is not goroutine safe!
+type SideBySideRecorder struct {
+	rows  []sideBySideRow
+	depth int
+}
+
+type sideBySideRow struct {
+	expected string
+	marker   string
+	actual   string
+	// note is printed on its own line instead of the columns
+	note string
+}
+
+func NewSideBySideRecorder() Recorder {
+	return &SideBySideRecorder{}
+}
+
+func (recorder *SideBySideRecorder) GetLog() string {
+	width := 0
+	for _, row := range recorder.rows {
+		width = max(width, utf8.RuneCountInString(row.expected))
+	}
+
+	var logResult strings.Builder
+	for _, row := range recorder.rows {
+		if row.note != "" {
+			logResult.WriteString(row.note)
+			continue
+		}
+
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(row.expected))
+		line := fmt.Sprintf("%s%s %s %s", row.expected, padding, row.marker, row.actual)
+		logResult.WriteString(strings.TrimRight(line, " "))
+		logResult.WriteString("\n")
+	}
+
+	return logResult.String()
+}
+
+func (recorder *SideBySideRecorder) Reset() {
+	*recorder = SideBySideRecorder{}
+}
+
+// The side-by-side output is built only from the pair callbacks, the regular ones are ignored.
+
+func (recorder *SideBySideRecorder) AppendFieldName(indent string, fieldName string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendIgnoreField(indent string, jsonPath string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendValue(indent string, path string, value any, kind reflect.Kind) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendValidationErrorSignal(message string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendMissingFieldErrorSignal(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendStartObject(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendEndObject(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendStartArray(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendEndArray(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendNewLine() Recorder {
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendPairStart(jsonPath string, fieldName string, kind reflect.Kind,
+	message string) Recorder {
+	line := recorder.indent() + formatFieldName(fieldName) + openingBracket(kind)
+	recorder.rows = append(recorder.rows,
+		sideBySideRow{expected: line, marker: lineMarker(message, changedLineMarker), actual: line})
+	recorder.depth++
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendPairEnd(jsonPath string, kind reflect.Kind) Recorder {
+	recorder.depth--
+	line := recorder.indent() + closingBracket(kind) + valueSeparator(jsonPath)
+	recorder.rows = append(recorder.rows, sideBySideRow{expected: line, marker: sameLineMarker, actual: line})
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendPair(jsonPath string, fieldName string, expected any, actual any,
+	message string) Recorder {
+	expectedLines := formatJsonLines(recorder.indent(), jsonPath, fieldName, expected)
+	actualLines := formatJsonLines(recorder.indent(), jsonPath, fieldName, actual)
+
+	for i := 0; i < max(len(expectedLines), len(actualLines)); i++ {
+		row := sideBySideRow{marker: lineMarker(message, changedLineMarker)}
+		if i < len(expectedLines) {
+			row.expected = expectedLines[i]
+		} else if message != "" {
+			row.marker = actualLineMarker
+		}
+		if i < len(actualLines) {
+			row.actual = actualLines[i]
+		} else if message != "" {
+			row.marker = expectedLineMarker
+		}
+		recorder.rows = append(recorder.rows, row)
+	}
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendMissingPair(jsonPath string, fieldName string, expected any) Recorder {
+	for _, line := range formatJsonLines(recorder.indent(), jsonPath, fieldName, expected) {
+		recorder.rows = append(recorder.rows, sideBySideRow{expected: line, marker: expectedLineMarker})
+	}
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendUnexpectedPair(jsonPath string, fieldName string, actual any) Recorder {
+	for _, line := range formatJsonLines(recorder.indent(), jsonPath, fieldName, actual) {
+		recorder.rows = append(recorder.rows, sideBySideRow{marker: actualLineMarker, actual: line})
+	}
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) AppendTruncation(message string) Recorder {
+	recorder.rows = append(recorder.rows, sideBySideRow{note: formatTruncation(message)})
+	return recorder
+}
+
+func (recorder *SideBySideRecorder) indent() string {
+	return strings.Repeat("  ", recorder.depth)
+}
+
+func lineMarker(message string, marker string) string {
+	if message == "" {
+		return sameLineMarker
+	}
+	return marker
+}
+
+// formatJsonLines pretty prints a value on one or more lines, in the same way as a JSON formatter would do it.
+func formatJsonLines(indent string, jsonPath string, fieldName string, value any) []string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent(indent, "  ")
+	if err := encoder.Encode(value); err != nil {
+		buffer.Reset()
+		buffer.WriteString(fmt.Sprintf("%v", value))
+	}
+
+	lines := strings.Split(strings.TrimRight(buffer.String(), "\n"), "\n")
+	lines[0] = indent + formatFieldName(fieldName) + lines[0]
+	lines[len(lines)-1] += valueSeparator(jsonPath)
+
+	return lines
+}
+
+func formatFieldName(fieldName string) string {
+	if fieldName == "" {
+		return ""
+	}
+
+	quotedName, _ := json.Marshal(fieldName)
+	return string(quotedName) + ": "
+}
+
+func valueSeparator(jsonPath string) string {
+	if jsonpath.IsRoot(jsonPath) {
+		return ""
+	}
+	return ","
+}
+
+func openingBracket(kind reflect.Kind) string {
+	if kind == reflect.Slice {
+		return "["
+	}
+	return "{"
+}
+
+func closingBracket(kind reflect.Kind) string {
+	if kind == reflect.Slice {
+		return "]"
+	}
+	return "}"
+}
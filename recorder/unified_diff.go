@@ -0,0 +1,211 @@
+package recorder
+
+import (
+	"github.com/go-clarum/clarum-json/internal/diff"
+	"github.com/go-clarum/clarum-json/internal/matcher"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"reflect"
+	"strings"
+)
+
+const unifiedDiffContextLines = 3
+
+// UnifiedDiffRecorder prints the differences between the expected and the actual JSON in the unified diff format,
+// so the output can be used with existing diff tools or pasted into a code review.
+//
+// Both documents are rebuilt from the comparison and pretty printed in a canonical form, with sorted keys & two spaces
+// indentation. Values that the comparison did not validate are masked: fields ignored with the '@ignore@' marker show the
+// marker on both sides and unexpected fields are only printed if the [Comparator] does a strict object check.
+// If the documents match, the log is empty.
+//
+// As this implementation keeps its state in a strings.Builder, it is not goroutine safe!
+type UnifiedDiffRecorder struct {
+	logResult strings.Builder
+	expected  documentBuilder
+	actual    documentBuilder
+}
+
+func NewUnifiedDiffRecorder() Recorder {
+	return &UnifiedDiffRecorder{}
+}
+
+func (recorder *UnifiedDiffRecorder) GetLog() string {
+	return recorder.logResult.String()
+}
+
+func (recorder *UnifiedDiffRecorder) Reset() {
+	*recorder = UnifiedDiffRecorder{}
+}
+
+// The unified diff is built only from the pair callbacks, the regular ones are ignored.
+
+func (recorder *UnifiedDiffRecorder) AppendFieldName(indent string, fieldName string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendIgnoreField(indent string, jsonPath string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendValue(indent string, path string, value any, kind reflect.Kind) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendValidationErrorSignal(message string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendMissingFieldErrorSignal(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendStartObject(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendEndObject(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendStartArray(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendEndArray(indent string, path string) Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendNewLine() Recorder {
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendPairStart(jsonPath string, fieldName string, kind reflect.Kind,
+	message string) Recorder {
+	recorder.expected.start(fieldName, kind)
+	recorder.actual.start(fieldName, kind)
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendPairEnd(jsonPath string, kind reflect.Kind) Recorder {
+	recorder.expected.end()
+	recorder.actual.end()
+	return recorder.appendDiffIfDone(jsonPath)
+}
+
+func (recorder *UnifiedDiffRecorder) AppendPair(jsonPath string, fieldName string, expected any, actual any,
+	message string) Recorder {
+	recorder.expected.add(fieldName, expected)
+	recorder.actual.add(fieldName, maskIgnored(expected, actual))
+	return recorder.appendDiffIfDone(jsonPath)
+}
+
+func (recorder *UnifiedDiffRecorder) AppendMissingPair(jsonPath string, fieldName string, expected any) Recorder {
+	recorder.expected.add(fieldName, expected)
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendUnexpectedPair(jsonPath string, fieldName string, actual any) Recorder {
+	recorder.actual.add(fieldName, actual)
+	return recorder
+}
+
+func (recorder *UnifiedDiffRecorder) AppendTruncation(message string) Recorder {
+	recorder.logResult.WriteString(formatTruncation(message))
+	return recorder
+}
+
+// appendDiffIfDone writes the diff of the rebuilt documents once the comparison reached the end of the root.
+func (recorder *UnifiedDiffRecorder) appendDiffIfDone(jsonPath string) Recorder {
+	if !jsonpath.IsRoot(jsonPath) {
+		return recorder
+	}
+
+	expectedLines := formatJsonLines("", jsonPath, "", recorder.expected.root)
+	actualLines := formatJsonLines("", jsonPath, "", recorder.actual.root)
+	recorder.logResult.WriteString(diff.Unified("expected", "actual", expectedLines, actualLines, unifiedDiffContextLines))
+
+	recorder.expected = documentBuilder{}
+	recorder.actual = documentBuilder{}
+	return recorder
+}
+
+// documentBuilder rebuilds a JSON document from the values reported during the comparison.
+type documentBuilder struct {
+	root  any
+	stack []*documentNode
+}
+
+type documentNode struct {
+	fieldName string
+	object    map[string]any
+	array     []any
+	kind      reflect.Kind
+}
+
+func (builder *documentBuilder) start(fieldName string, kind reflect.Kind) {
+	node := &documentNode{fieldName: fieldName, kind: kind}
+	if kind == reflect.Map {
+		node.object = map[string]any{}
+	} else {
+		node.array = []any{}
+	}
+	builder.stack = append(builder.stack, node)
+}
+
+func (builder *documentBuilder) end() {
+	node := builder.stack[len(builder.stack)-1]
+	builder.stack = builder.stack[:len(builder.stack)-1]
+
+	if node.kind == reflect.Map {
+		builder.add(node.fieldName, node.object)
+	} else {
+		builder.add(node.fieldName, node.array)
+	}
+}
+
+func (builder *documentBuilder) add(fieldName string, value any) {
+	if len(builder.stack) == 0 {
+		builder.root = value
+		return
+	}
+
+	parent := builder.stack[len(builder.stack)-1]
+	if parent.kind == reflect.Map {
+		parent.object[fieldName] = value
+	} else {
+		parent.array = append(parent.array, value)
+	}
+}
+
+// maskIgnored returns a copy of the actual value where every value ignored by the expected one is
+// replaced with the ignore marker.
+func maskIgnored(expected any, actual any) any {
+	if matcher.IsIgnore(expected) {
+		return expected
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]any:
+		if actualObject, ok := actual.(map[string]any); ok {
+			result := make(map[string]any, len(actualObject))
+			for key, actualValue := range actualObject {
+				result[key] = maskIgnored(expectedValue[key], actualValue)
+			}
+			return result
+		}
+	case []any:
+		if actualArray, ok := actual.([]any); ok {
+			result := make([]any, len(actualArray))
+			for i, actualValue := range actualArray {
+				if i < len(expectedValue) {
+					result[i] = maskIgnored(expectedValue[i], actualValue)
+				} else {
+					result[i] = actualValue
+				}
+			}
+			return result
+		}
+	}
+
+	return actual
+}
@@ -0,0 +1,29 @@
+package report
+
+import (
+	"errors"
+	"github.com/go-clarum/clarum-json/batch"
+)
+
+var (
+	errMissingActual    = errors.New("missing actual - no actual file with the same relative path")
+	errUnexpectedActual = errors.New("unexpected actual - no expected file with the same relative path")
+)
+
+// FromSummary converts the result of a directory comparison into a suite, with one result per file.
+// Files that only exist in one of the directories are reported as errors.
+func FromSummary(name string, summary *batch.Summary) Suite {
+	suite := Suite{Name: name}
+	for _, fileResult := range summary.Results {
+		result := Result{Name: fileResult.Path, Err: fileResult.Err, Log: fileResult.Log, Duration: fileResult.Duration}
+
+		switch fileResult.Status {
+		case batch.MissingActual:
+			result.Err = errMissingActual
+		case batch.UnexpectedActual:
+			result.Err = errUnexpectedActual
+		}
+		suite.Results = append(suite.Results, result)
+	}
+	return suite
+}
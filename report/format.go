@@ -0,0 +1,85 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-json/comparator"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrorKind is the kind under which the errors that are not mismatches are counted.
+const ErrorKind = "error"
+
+// absentValue is shown for the side of a mismatch that has no value, like the actual value of a missing field.
+const absentValue = "-"
+
+// kindCount is the number of findings of one kind.
+type kindCount struct {
+	Kind  string
+	Count int
+}
+
+// countKinds returns the number of mismatches per kind and the number of other errors, sorted by kind.
+func countKinds(suites []Suite) []kindCount {
+	counts := make(map[string]int)
+	for _, suite := range suites {
+		for _, result := range suite.Results {
+			for _, mismatch := range result.Mismatches() {
+				counts[string(mismatch.Kind)]++
+			}
+			if errorCount := len(result.Errors()); errorCount > 0 {
+				counts[ErrorKind] += errorCount
+			}
+		}
+	}
+
+	var kindCounts []kindCount
+	for kind, count := range counts {
+		kindCounts = append(kindCounts, kindCount{kind, count})
+	}
+	sort.Slice(kindCounts, func(i, j int) bool {
+		return kindCounts[i].Kind < kindCounts[j].Kind
+	})
+	return kindCounts
+}
+
+// mismatchValues formats the expected & actual values of the mismatch, truncated to the maximum length.
+func mismatchValues(mismatch *comparator.Mismatch, maxLength int) (string, string) {
+	expected, actual := formatValue(mismatch.Expected, maxLength), formatValue(mismatch.Actual, maxLength)
+
+	switch mismatch.Kind {
+	case comparator.MissingField:
+		actual = absentValue
+	case comparator.UnexpectedField:
+		expected = absentValue
+	}
+	return expected, actual
+}
+
+// mismatchDetail returns the message of the mismatch without the path it starts with.
+func mismatchDetail(mismatch *comparator.Mismatch) string {
+	return strings.TrimPrefix(mismatch.Error(), fmt.Sprintf("[%s] - ", mismatch.Path))
+}
+
+// formatValue formats the value as compact JSON. Values longer than the maximum length are truncated,
+// a maximum length of 0 or less means no limit.
+func formatValue(value any, maxLength int) string {
+	var formatted strings.Builder
+	encoder := json.NewEncoder(&formatted)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return truncate(fmt.Sprintf("%v", value), maxLength)
+	}
+	return truncate(strings.TrimSuffix(formatted.String(), "\n"), maxLength)
+}
+
+func truncate(text string, maxLength int) string {
+	if maxLength <= 0 || utf8.RuneCountInString(text) <= maxLength {
+		return text
+	}
+
+	runes := []rune(text)
+	return string(runes[:max(maxLength-1, 0)]) + "…"
+}
@@ -0,0 +1,316 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// htmlMaxValueLength is the maximum length of the values in the findings table, the full value is in the tooltip.
+const htmlMaxValueLength = 120
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #24292f; }
+code, pre, .tree { font-family: ui-monospace, monospace; font-size: 13px; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+.passed { color: #1a7f37; }
+.failed, .error { color: #cf222e; }
+.badge { display: inline-block; min-width: 4em; padding: 0 6px; border-radius: 8px; color: #fff; text-align: center; }
+.badge.passed { background: #1a7f37; }
+.badge.failed, .badge.error { background: #cf222e; }
+.duration { color: #57606a; font-size: smaller; }
+details.result { border: 1px solid #d0d7de; border-radius: 6px; padding: 8px; margin-bottom: 8px; }
+details.result > summary { cursor: pointer; font-weight: 600; }
+#filter { width: 30em; padding: 4px; margin-bottom: 8px; }
+.trees { display: flex; gap: 2em; }
+.tree { flex: 1; overflow-x: auto; }
+.tree details { margin-left: 1.2em; }
+.tree .leaf { margin-left: 2.4em; }
+.tree > details { margin-left: 0; }
+.tree > .leaf { margin-left: 0; }
+.key { color: #0550ae; }
+.mismatch > summary, .leaf.mismatch { background: #ffebe9; }
+.message { color: #cf222e; font-family: system-ui, sans-serif; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Total}} comparison(s) - <span class="passed">{{.Passed}} passed</span>, <span class="failed">{{.Failed}} failed</span>, <span class="error">{{.Errored}} error(s)</span></p>
+{{- if .Kinds}}
+<h2>Findings per kind</h2>
+<table>
+<tr><th>Kind</th><th>Count</th></tr>
+{{- range .Kinds}}
+<tr><td>{{.Kind}}</td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Findings}}
+<h2>Findings</h2>
+<input id="filter" type="search" placeholder="Filter by JSONPath, kind or comparison" oninput="filterFindings(this.value)">
+<table id="findings">
+<thead><tr><th>Comparison</th><th>JSONPath</th><th>Kind</th><th>Expected</th><th>Actual</th></tr></thead>
+<tbody>
+{{- range .Findings}}
+<tr data-filter="{{.Filter}}"><td><a href="#{{.Anchor}}">{{.Comparison}}</a></td><td><code>{{.Path}}</code></td><td>{{.Kind}}</td><td title="{{.FullExpected}}"><code>{{.Expected}}</code></td><td title="{{.FullActual}}"><code>{{.Actual}}</code></td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- range .Suites}}
+<h2>{{.Name}}</h2>
+{{- range .Results}}
+<details class="result" id="{{.Anchor}}"{{if ne .Status "passed"}} open{{end}}>
+<summary><span class="badge {{.Status}}">{{.Status}}</span> {{.Name}} <span class="duration">{{.Duration}}</span></summary>
+{{- if .Errors}}
+<ul class="error">
+{{- range .Errors}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .HasTrees}}
+<div class="trees">
+<div class="tree"><h3>Expected</h3>
+{{.Expected}}</div>
+<div class="tree"><h3>Actual</h3>
+{{.Actual}}</div>
+</div>
+{{- end}}
+{{- if .Log}}
+<details><summary>Recorder output</summary><pre>{{.Log}}</pre></details>
+{{- end}}
+</details>
+{{- end}}
+{{- end}}
+<script>
+function filterFindings(text) {
+  text = text.toLowerCase();
+  document.querySelectorAll('#findings tbody tr').forEach(function (row) {
+    row.style.display = row.dataset.filter.includes(text) ? '' : 'none';
+  });
+}
+</script>
+</body>
+</html>
+`))
+
+type htmlReport struct {
+	Title    string
+	Total    int
+	Passed   int
+	Failed   int
+	Errored  int
+	Kinds    []kindCount
+	Findings []htmlFinding
+	Suites   []htmlSuite
+}
+
+type htmlFinding struct {
+	Comparison   string
+	Anchor       string
+	Path         string
+	Kind         string
+	Expected     string
+	Actual       string
+	FullExpected string
+	FullActual   string
+	Filter       string
+}
+
+type htmlSuite struct {
+	Name    string
+	Results []htmlResult
+}
+
+type htmlResult struct {
+	Name     string
+	Anchor   string
+	Status   string
+	Duration string
+	Errors   []string
+	HasTrees bool
+	Expected template.HTML
+	Actual   template.HTML
+	Log      string
+}
+
+// WriteHTML writes the suites as a single static HTML page, without external resources, that can be opened
+// in any browser or archived by a CI system. It contains the number of findings per kind, a table of all mismatches
+// that can be filtered by JSONPath, kind or comparison, and a section per result.
+//
+// If a result contains the compared JSONs, both are shown as collapsible trees with the mismatches highlighted
+// inline; otherwise the recorder output is shown.
+func WriteHTML(output io.Writer, title string, suites ...Suite) error {
+	page := htmlReport{Title: title, Kinds: countKinds(suites)}
+
+	for suiteIndex, suite := range suites {
+		passed, failed, errored := suite.Counts()
+		page.Total += len(suite.Results)
+		page.Passed += passed
+		page.Failed += failed
+		page.Errored += errored
+
+		htmlSuite := htmlSuite{Name: suite.Name}
+		for resultIndex, result := range suite.Results {
+			anchor := fmt.Sprintf("result-%d-%d", suiteIndex+1, resultIndex+1)
+			comparison := result.Name
+			if suite.Name != "" {
+				comparison = suite.Name + " / " + result.Name
+			}
+
+			for _, mismatch := range result.Mismatches() {
+				expected, actual := mismatchValues(mismatch, htmlMaxValueLength)
+				fullExpected, fullActual := mismatchValues(mismatch, 0)
+				page.Findings = append(page.Findings, htmlFinding{
+					Comparison:   comparison,
+					Anchor:       anchor,
+					Path:         mismatch.Path,
+					Kind:         string(mismatch.Kind),
+					Expected:     expected,
+					Actual:       actual,
+					FullExpected: fullExpected,
+					FullActual:   fullActual,
+					Filter:       strings.ToLower(strings.Join([]string{comparison, mismatch.Path, string(mismatch.Kind)}, " ")),
+				})
+			}
+
+			htmlSuite.Results = append(htmlSuite.Results, newHtmlResult(result, anchor))
+		}
+		page.Suites = append(page.Suites, htmlSuite)
+	}
+
+	return htmlTemplate.Execute(output, page)
+}
+
+func newHtmlResult(result Result, anchor string) htmlResult {
+	htmlResult := htmlResult{
+		Name:     result.Name,
+		Anchor:   anchor,
+		Status:   status(result),
+		Duration: result.Duration.Round(time.Microsecond).String(),
+	}
+	for _, err := range result.Errors() {
+		htmlResult.Errors = append(htmlResult.Errors, err.Error())
+	}
+
+	if result.Expected != nil && result.Actual != nil {
+		messages := make(map[string][]string)
+		for _, mismatch := range result.Mismatches() {
+			messages[mismatch.Pointer] = append(messages[mismatch.Pointer], mismatchDetail(mismatch))
+		}
+
+		htmlResult.HasTrees = true
+		htmlResult.Expected = renderTree(result.Expected, messages)
+		htmlResult.Actual = renderTree(result.Actual, messages)
+	} else {
+		htmlResult.Log = result.Log
+	}
+	return htmlResult
+}
+
+func status(result Result) string {
+	switch {
+	case result.Passed():
+		return "passed"
+	case result.Failed():
+		return "failed"
+	default:
+		return "error"
+	}
+}
+
+// renderTree renders the JSON as nested details elements. The nodes at the pointers of the messages are highlighted
+// and only the objects & arrays that contain such a node are expanded. Invalid JSON is shown as it is.
+func renderTree(document []byte, messages map[string][]string) template.HTML {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var jsonObject any
+	if err := decoder.Decode(&jsonObject); err != nil {
+		return template.HTML("<pre>" + template.HTMLEscapeString(string(document)) + "</pre>")
+	}
+
+	var tree strings.Builder
+	renderNode(&tree, "", jsonpath.RootPointer, jsonObject, messages, parentPointers(messages))
+	return template.HTML(tree.String())
+}
+
+func renderNode(tree *strings.Builder, label string, pointer string, value any, messages map[string][]string,
+	expanded map[string]bool) {
+	class, message := "", ""
+	if nodeMessages, exists := messages[pointer]; exists {
+		class = " mismatch"
+		message = ` <span class="message">&larr; ` + template.HTMLEscapeString(strings.Join(nodeMessages, "; ")) + `</span>`
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		openContainer(tree, class, label, fmt.Sprintf("{%d field(s)}", len(typedValue)), message,
+			pointer == jsonpath.RootPointer || expanded[pointer])
+		for _, key := range keys {
+			renderNode(tree, template.HTMLEscapeString(fmt.Sprintf("%q", key)),
+				jsonpath.GetObjectChildPointer(pointer, key), typedValue[key], messages, expanded)
+		}
+		tree.WriteString("</details>\n")
+	case []any:
+		openContainer(tree, class, label, fmt.Sprintf("[%d item(s)]", len(typedValue)), message,
+			pointer == jsonpath.RootPointer || expanded[pointer])
+		for i, item := range typedValue {
+			renderNode(tree, fmt.Sprintf("%d", i), jsonpath.GetArrayIndexPointer(pointer, i), item, messages, expanded)
+		}
+		tree.WriteString("</details>\n")
+	default:
+		tree.WriteString(`<div class="leaf` + class + `">` + formatLabel(label) +
+			template.HTMLEscapeString(formatValue(value, 0)) + message + "</div>\n")
+	}
+}
+
+func openContainer(tree *strings.Builder, class string, label string, size string, message string, open bool) {
+	openAttribute := ""
+	if open {
+		openAttribute = " open"
+	}
+	tree.WriteString(`<details class="node` + class + `"` + openAttribute + `><summary>` + formatLabel(label) +
+		size + message + "</summary>\n")
+}
+
+func formatLabel(label string) string {
+	if label == "" {
+		return ""
+	}
+	return `<span class="key">` + label + `</span>: `
+}
+
+// parentPointers returns the pointers of all objects & arrays that contain one of the pointers.
+// The slashes of the keys are escaped, so every slash of a pointer starts the pointer of a child.
+func parentPointers[V any](pointers map[string]V) map[string]bool {
+	parents := make(map[string]bool)
+	for pointer := range pointers {
+		for i := range pointer {
+			if pointer[i] == '/' {
+				parents[pointer[:i]] = true
+			}
+		}
+	}
+	return parents
+}
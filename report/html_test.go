@@ -0,0 +1,80 @@
+package report
+
+import (
+	"bytes"
+	"github.com/go-clarum/clarum-json/comparator"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTML(t *testing.T) {
+	passed, failed, errored := testResults(t)
+	failed.Expected, failed.Actual = []byte("{\"name\": \"Bruce\", \"city\": \"Gotham\"}"),
+		[]byte("{\"name\": \"Dick\", \"city\": \"Gotham\"}")
+
+	var output bytes.Buffer
+	if err := WriteHTML(&output, "clarum-json", Suite{Name: "customers", Results: []Result{passed, failed, errored}}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkContains(t, output.String(),
+		"<title>clarum-json</title>",
+		"3 comparison(s) - <span class=\"passed\">1 passed</span>, <span class=\"failed\">1 failed</span>, <span class=\"error\">1 error(s)</span>",
+		"<tr><td>error</td><td>1</td></tr>",
+		"<tr><td>value mismatch</td><td>1</td></tr>",
+		"<tr data-filter=\"customers / result-2 $.name value mismatch\"><td><a href=\"#result-1-2\">customers / result-2</a></td>"+
+			"<td><code>$.name</code></td><td>value mismatch</td>"+
+			"<td title=\"&#34;Bruce&#34;\"><code>&#34;Bruce&#34;</code></td><td title=\"&#34;Dick&#34;\"><code>&#34;Dick&#34;</code></td></tr>",
+		"<details class=\"result\" id=\"result-1-1\">",
+		"<details class=\"result\" id=\"result-1-2\" open>",
+		"<div class=\"leaf\"><span class=\"key\">&#34;city&#34;</span>: &#34;Gotham&#34;</div>",
+		"<div class=\"leaf mismatch\"><span class=\"key\">&#34;name&#34;</span>: &#34;Dick&#34; "+
+			"<span class=\"message\">&larr; value mismatch - expected [Bruce] but received [Dick]</span></div>",
+		"<li>unable to parse JSON - error [unexpected end of JSON input] - from string [{&#34;name&#34;: ]</li>",
+		"<details><summary>Recorder output</summary><pre>",
+	)
+}
+
+func TestWriteHTMLEscaping(t *testing.T) {
+	_, err := comparator.NewComparator().Build().Compare([]byte("{\"<b>\": \"<i>\"}"), []byte("{\"<b>\": \"</i>\"}"))
+	result := Result{Name: "<script>", Err: err, Expected: []byte("{\"<b>\": \"<i>\"}"), Actual: []byte("<invalid>")}
+
+	var output bytes.Buffer
+	if err := WriteHTML(&output, "<title>", Suite{Name: "escaping", Results: []Result{result}}); err != nil {
+		t.Fatal(err)
+	}
+
+	html := output.String()
+	for _, unescaped := range []string{"<b>", "<i>", "</i>", "<invalid>", "<script>&", "<title><"} {
+		if strings.Contains(html, unescaped) {
+			t.Errorf("the report contains the unescaped [%s]:\n%s", unescaped, html)
+		}
+	}
+	checkContains(t, html, "<pre>&lt;invalid&gt;</pre>", "<span class=\"key\">&#34;&lt;b&gt;&#34;</span>")
+}
+
+func TestRenderTree(t *testing.T) {
+	tree := string(renderTree([]byte("{\"a\": {\"b\": [1, 2]}, \"c\": {\"d/e\": 1}}"),
+		map[string][]string{"/a/b/1": {"value mismatch"}, "/c/d~1e": {"missing field"}}))
+
+	checkContains(t, tree,
+		"<details class=\"node\" open><summary>{2 field(s)}</summary>",
+		"<details class=\"node\" open><summary><span class=\"key\">&#34;b&#34;</span>: [2 item(s)]</summary>",
+		"<div class=\"leaf\"><span class=\"key\">0</span>: 1</div>",
+		"<div class=\"leaf mismatch\"><span class=\"key\">1</span>: 2 <span class=\"message\">&larr; value mismatch</span></div>",
+		"<details class=\"node\" open><summary><span class=\"key\">&#34;c&#34;</span>: {1 field(s)}</summary>",
+		"<div class=\"leaf mismatch\"><span class=\"key\">&#34;d/e&#34;</span>: 1",
+	)
+
+	collapsed := string(renderTree([]byte("{\"a\": {\"b\": 1}}"), nil))
+	checkContains(t, collapsed, "<details class=\"node\"><summary><span class=\"key\">&#34;a&#34;</span>: {1 field(s)}</summary>")
+}
+
+func checkContains(t *testing.T, text string, expected ...string) {
+	t.Helper()
+	for _, fragment := range expected {
+		if !strings.Contains(text, fragment) {
+			t.Errorf("the output does not contain [%s]:\n%s", fragment, text)
+		}
+	}
+}
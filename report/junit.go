@@ -0,0 +1,132 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// WriteJUnit writes the suites in the JUnit XML format, which most CI systems display as test results.
+// Every result is a test case: results with mismatches are failures and results that could not be compared
+// are errors. The text of failures & errors lists all errors of the comparison, followed by the recorder output.
+func WriteJUnit(output io.Writer, name string, suites ...Suite) error {
+	testSuites := junitTestSuites{Name: name}
+
+	var duration time.Duration
+	for _, suite := range suites {
+		_, failed, errored := suite.Counts()
+		testSuite := junitTestSuite{
+			Name:     suite.Name,
+			Tests:    len(suite.Results),
+			Failures: failed,
+			Errors:   errored,
+			Time:     seconds(suite.Duration()),
+		}
+
+		for _, result := range suite.Results {
+			testSuite.TestCases = append(testSuite.TestCases, newJunitTestCase(suite.Name, result))
+		}
+
+		testSuites.Suites = append(testSuites.Suites, testSuite)
+		testSuites.Tests += testSuite.Tests
+		testSuites.Failures += failed
+		testSuites.Errors += errored
+		duration += suite.Duration()
+	}
+	testSuites.Time = seconds(duration)
+
+	if _, err := io.WriteString(output, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(output)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(testSuites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(output, "\n")
+	return err
+}
+
+func newJunitTestCase(suiteName string, result Result) junitTestCase {
+	testCase := junitTestCase{Name: result.Name, ClassName: suiteName, Time: seconds(result.Duration)}
+
+	switch {
+	case result.Passed():
+		return testCase
+	case result.Failed():
+		testCase.Failure = &junitProblem{
+			Message: fmt.Sprintf("[%d] mismatch(es)", len(result.Mismatches())),
+			Type:    "mismatch",
+			Text:    details(result),
+		}
+	default:
+		testCase.Error = &junitProblem{
+			Message: strings.Map(xmlChar, firstLine(result.Err.Error())),
+			Type:    "error",
+			Text:    details(result),
+		}
+	}
+	return testCase
+}
+
+// details returns all errors of the comparison, one per line, followed by the recorder output.
+// Characters that are not allowed in XML, like the escape sequences of colored output, are removed.
+func details(result Result) string {
+	text := result.Err.Error() + "\n"
+	if result.Log != "" {
+		text += "\n" + result.Log
+	}
+	return strings.Map(xmlChar, text)
+}
+
+// xmlChar drops the control characters that XML 1.0 does not allow, even in a CDATA section.
+func xmlChar(char rune) rune {
+	if char < 0x20 && char != '\t' && char != '\n' && char != '\r' {
+		return -1
+	}
+	return char
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
+
+func seconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	passed, failed, errored := testResults(t)
+
+	var output bytes.Buffer
+	err := WriteJUnit(&output, "clarum-json",
+		Suite{Name: "customers", Results: []Result{passed, failed, errored}},
+		Suite{Name: "empty"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkReport(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="clarum-json" tests="3" failures="1" errors="1" time="0.006">
+  <testsuite name="customers" tests="3" failures="1" errors="1" time="0.006">
+    <testcase name="result-1" classname="customers" time="0.001"></testcase>
+    <testcase name="result-2" classname="customers" time="0.002">
+      <failure message="[1] mismatch(es)" type="mismatch"><![CDATA[[$.name] - value mismatch - expected [Bruce] but received [Dick]
+
+{
+  "name": Dick, <-- value mismatch - expected [Bruce]
+}
+]]></failure>
+    </testcase>
+    <testcase name="result-3" classname="customers" time="0.003">
+      <error message="unable to parse JSON - error [unexpected end of JSON input] - from string [{&#34;name&#34;: ]" type="error"><![CDATA[unable to parse JSON - error [unexpected end of JSON input] - from string [{"name": ]
+]]></error>
+    </testcase>
+  </testsuite>
+  <testsuite name="empty" tests="0" failures="0" errors="0" time="0.000"></testsuite>
+</testsuites>
+`, output.String())
+}
+
+func TestWriteJUnitEscaping(t *testing.T) {
+	result := Result{Name: "<a & b>", Err: errors.New("\033[31mfailed\033[0m ]]> <end>"), Log: "line\x00"}
+
+	var output bytes.Buffer
+	if err := WriteJUnit(&output, "", Suite{Name: "escaping", Results: []Result{result}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed junitTestSuites
+	if err := xml.Unmarshal(output.Bytes(), &parsed); err != nil {
+		t.Fatalf("invalid XML: %s\n%s", err, output.String())
+	}
+
+	testCase := parsed.Suites[0].TestCases[0]
+	if testCase.Name != "<a & b>" || testCase.Error == nil || testCase.Error.Message != "[31mfailed[0m ]]> <end>" ||
+		testCase.Error.Text != "[31mfailed[0m ]]> <end>\n\nline" {
+		t.Errorf("wrong test case: %+v", testCase.Error)
+	}
+	if strings.Contains(output.String(), "testsuites name=") {
+		t.Error("the name of the report must be omitted if empty")
+	}
+}
+
+func checkReport(t *testing.T, expected string, actual string) {
+	if expected != actual {
+		t.Errorf("wrong report:\n%s", actual)
+	}
+}
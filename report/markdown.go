@@ -0,0 +1,258 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-json/internal/diff"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"io"
+	"strings"
+)
+
+const (
+	// markdownMaxValueLength is the maximum length of the values in the findings table.
+	markdownMaxValueLength = 80
+	// markdownMaxLineLength is the maximum length of a line of the diff.
+	markdownMaxLineLength = 200
+	// markdownMaxDiffLines is the maximum number of lines of the diff, so that the comment stays below the size
+	// limits of the code review tools.
+	markdownMaxDiffLines = 300
+	markdownContextLines = 3
+)
+
+// WriteMarkdown writes the results that did not pass as Markdown, to be posted as a comment on a pull request.
+// Every result has a table of its mismatches, with the JSONPath, kind, expected & actual value, followed by
+// the other errors and a collapsible diff. Long values & diffs are truncated.
+//
+// If the recorder output is a unified diff, as written by a [recorder.UnifiedDiffRecorder], it is shown as it is.
+// Otherwise the diff is computed from Expected & Actual if the result contains the compared JSONs; it only contains
+// the mismatches of the result, so ignored values and the extra fields allowed by a non-strict comparison are not
+// shown as differences. Without the compared JSONs, the recorder output is shown.
+func WriteMarkdown(output io.Writer, title string, suites ...Suite) error {
+	var text strings.Builder
+
+	var total, passed, failed, errored int
+	for _, suite := range suites {
+		suitePassed, suiteFailed, suiteErrored := suite.Counts()
+		total += len(suite.Results)
+		passed += suitePassed
+		failed += suiteFailed
+		errored += suiteErrored
+	}
+
+	if title != "" {
+		fmt.Fprintf(&text, "### %s\n\n", escapeMarkdown(title))
+	}
+	fmt.Fprintf(&text, "%d comparison(s) - %d passed, %d failed, %d error(s)\n", total, passed, failed, errored)
+
+	for _, suite := range suites {
+		for _, result := range suite.Results {
+			if !result.Passed() {
+				writeMarkdownResult(&text, suite.Name, result)
+			}
+		}
+	}
+
+	_, err := io.WriteString(output, text.String())
+	return err
+}
+
+func writeMarkdownResult(text *strings.Builder, suiteName string, result Result) {
+	name := result.Name
+	if suiteName != "" {
+		name = suiteName + " / " + result.Name
+	}
+	fmt.Fprintf(text, "\n#### %s - %s\n", escapeMarkdown(name), status(result))
+
+	if mismatches := result.Mismatches(); len(mismatches) > 0 {
+		text.WriteString("\n| Path | Kind | Expected | Actual |\n|------|------|----------|--------|\n")
+		for _, mismatch := range mismatches {
+			expected, actual := mismatchValues(mismatch, markdownMaxValueLength)
+			fmt.Fprintf(text, "| %s | %s | %s | %s |\n", codeSpan(mismatch.Path), mismatch.Kind,
+				markdownValue(expected), markdownValue(actual))
+		}
+	}
+
+	if errors := result.Errors(); len(errors) > 0 {
+		text.WriteString("\n")
+		for _, err := range errors {
+			fmt.Fprintf(text, "- %s\n", escapeMarkdown(firstLine(err.Error())))
+		}
+	}
+
+	content, isDiff := markdownDiff(result)
+	if content == "" {
+		return
+	}
+
+	summary, language := "Recorder output", ""
+	if isDiff {
+		summary, language = "Diff", "diff"
+	}
+	fence := codeFence(content)
+	fmt.Fprintf(text, "\n<details>\n<summary>%s</summary>\n\n%s%s\n%s\n%s\n\n</details>\n",
+		summary, fence, language, content, fence)
+}
+
+// markdownDiff returns the truncated unified diff of the compared JSONs, or the recorder output if it is already
+// a diff or the result does not contain the JSONs, and if the content is a diff.
+func markdownDiff(result Result) (string, bool) {
+	content := strings.TrimRight(result.Log, "\n")
+	isDiff := strings.HasPrefix(content, "--- ")
+	if !isDiff {
+		if expectedLines, actualLines, valid := comparedLines(result); valid {
+			content = strings.TrimRight(diff.Unified("expected", "actual", expectedLines, actualLines,
+				markdownContextLines), "\n")
+			isDiff = true
+		}
+	}
+	if content == "" {
+		return "", false
+	}
+
+	lines := strings.Split(content, "\n")
+	omitted := len(lines) - markdownMaxDiffLines
+	if omitted > 0 {
+		lines = append(lines[:markdownMaxDiffLines], fmt.Sprintf("... [%d] more line(s)", omitted))
+	}
+	for i, line := range lines {
+		lines[i] = truncate(line, markdownMaxLineLength)
+	}
+	return strings.Join(lines, "\n"), isDiff
+}
+
+// comparedLines formats the compared JSONs for the diff. The values of the actual JSON that were not reported
+// as mismatches are replaced by the expected ones, so that the diff only contains the mismatches.
+// It returns false if one of the documents is missing or invalid.
+func comparedLines(result Result) ([]string, []string, bool) {
+	expected, expectedValid := decodeDocument(result.Expected)
+	actual, actualValid := decodeDocument(result.Actual)
+	if !expectedValid || !actualValid {
+		return nil, nil, false
+	}
+
+	reported := make(map[string]bool)
+	for _, mismatch := range result.Mismatches() {
+		reported[mismatch.Pointer] = true
+	}
+	actual = reportedValue(expected, actual, jsonpath.RootPointer, reported, parentPointers(reported))
+
+	expectedLines, expectedValid := canonicalLines(expected)
+	actualLines, actualValid := canonicalLines(actual)
+	return expectedLines, actualLines, expectedValid && actualValid
+}
+
+func decodeDocument(document []byte) (any, bool) {
+	if document == nil {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	var jsonObject any
+	if err := decoder.Decode(&jsonObject); err != nil {
+		return nil, false
+	}
+	return jsonObject, true
+}
+
+// reportedValue returns the actual value as it is shown in the diff. A value without a mismatch is replaced by
+// the expected one, while the objects & arrays that contain mismatches are checked field by field or item by item.
+// Missing fields are only left out and unexpected fields only kept if they were reported.
+func reportedValue(expected any, actual any, pointer string, reported map[string]bool, parents map[string]bool) any {
+	if !parents[pointer] {
+		if reported[pointer] {
+			return actual
+		}
+		return expected
+	}
+
+	switch expectedValue := expected.(type) {
+	case map[string]any:
+		if actualObject, isObject := actual.(map[string]any); isObject {
+			result := make(map[string]any, len(actualObject))
+			for key, value := range expectedValue {
+				childPointer := jsonpath.GetObjectChildPointer(pointer, key)
+				if actualValue, exists := actualObject[key]; exists {
+					result[key] = reportedValue(value, actualValue, childPointer, reported, parents)
+				} else if !reported[childPointer] {
+					result[key] = value
+				}
+			}
+			for key, actualValue := range actualObject {
+				if _, exists := expectedValue[key]; !exists && reported[jsonpath.GetObjectChildPointer(pointer, key)] {
+					result[key] = actualValue
+				}
+			}
+			return result
+		}
+	case []any:
+		if actualArray, isArray := actual.([]any); isArray && len(actualArray) == len(expectedValue) {
+			result := make([]any, len(actualArray))
+			for i, actualValue := range actualArray {
+				result[i] = reportedValue(expectedValue[i], actualValue, jsonpath.GetArrayIndexPointer(pointer, i),
+					reported, parents)
+			}
+			return result
+		}
+	}
+	return actual
+}
+
+// canonicalLines formats the JSON with sorted keys & two spaces indentation.
+func canonicalLines(jsonObject any) ([]string, bool) {
+	var formatted strings.Builder
+	encoder := json.NewEncoder(&formatted)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(jsonObject); err != nil {
+		return nil, false
+	}
+	return strings.Split(strings.TrimSuffix(formatted.String(), "\n"), "\n"), true
+}
+
+func markdownValue(value string) string {
+	if value == absentValue {
+		return value
+	}
+	return codeSpan(value)
+}
+
+// codeSpan wraps the text in enough backticks to contain the backticks of the text. Pipes are escaped,
+// as they would end the table cell even inside a code span.
+func codeSpan(text string) string {
+	delimiter := strings.Repeat("`", longestRun(text, '`')+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return delimiter + strings.ReplaceAll(text, "|", "\\|") + delimiter
+}
+
+// codeFence returns a fence longer than any backtick sequence of the content.
+func codeFence(content string) string {
+	return strings.Repeat("`", max(longestRun(content, '`')+1, 3))
+}
+
+func longestRun(text string, char rune) int {
+	longest, current := 0, 0
+	for _, c := range text {
+		if c == char {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	return longest
+}
+
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "`", "\\`", "*", "\\*", "_", "\\_", "[", "\\[", "]", "\\]",
+	"<", "&lt;", ">", "&gt;", "|", "\\|", "#", "\\#", "\n", " ",
+)
+
+// escapeMarkdown escapes the text so that it is shown as it is on a single line.
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
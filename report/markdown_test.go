@@ -0,0 +1,124 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/recorder"
+	"strings"
+	"testing"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	passed, failed, errored := testResults(t)
+	failed.Expected, failed.Actual = []byte("{\"name\": \"Bruce\", \"city\": \"Gotham\"}"),
+		[]byte("{\"city\": \"Gotham\", \"name\": \"Dick\"}")
+
+	var output bytes.Buffer
+	if err := WriteMarkdown(&output, "clarum-json", Suite{Name: "customers", Results: []Result{passed, failed, errored}}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkReport(t, "### clarum-json\n\n"+
+		"3 comparison(s) - 1 passed, 1 failed, 1 error(s)\n\n"+
+		"#### customers / result-2 - failed\n\n"+
+		"| Path | Kind | Expected | Actual |\n"+
+		"|------|------|----------|--------|\n"+
+		"| `$.name` | value mismatch | `\"Bruce\"` | `\"Dick\"` |\n\n"+
+		"<details>\n<summary>Diff</summary>\n\n"+
+		"```diff\n"+
+		"--- expected\n+++ actual\n@@ -1,4 +1,4 @@\n {\n   \"city\": \"Gotham\",\n-  \"name\": \"Bruce\"\n+  \"name\": \"Dick\"\n }\n"+
+		"```\n\n</details>\n\n"+
+		"#### customers / result-3 - error\n\n"+
+		"- unable to parse JSON - error \\[unexpected end of JSON input\\] - from string \\[{\"name\": \\]\n",
+		output.String())
+}
+
+func TestWriteMarkdownRecorderLog(t *testing.T) {
+	jsonComparator := comparator.NewComparator().Recorder(recorder.NewUnifiedDiffRecorder()).Build()
+	recorderLog, err := jsonComparator.Compare([]byte("{\"id\": 1, \"tags\": [\"a\"]}"), []byte("{\"tags\": [\"a|b\"]}"))
+
+	var output bytes.Buffer
+	if err := WriteMarkdown(&output, "", Suite{Results: []Result{{Name: "GET /customers/1", Err: err, Log: recorderLog}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkContains(t, output.String(),
+		"#### GET /customers/1 - failed\n",
+		"| `$.id` | missing field | `1` | - |\n",
+		"| `$.tags[0]` | value mismatch | `\"a\"` | `\"a\\|b\"` |\n",
+		"<summary>Diff</summary>\n\n```diff\n--- expected\n+++ actual\n",
+	)
+	if strings.HasPrefix(output.String(), "###") {
+		t.Errorf("the report must not have a title:\n%s", output.String())
+	}
+}
+
+func TestWriteMarkdownDiffOfMismatchesOnly(t *testing.T) {
+	expected := []byte("{\"id\": \"@ignore@\", \"name\": \"Bruce\", \"meta\": {\"version\": 1}, \"tags\": [\"a\"]}")
+	actual := []byte("{\"id\": 7, \"name\": \"Dick\", \"meta\": {\"version\": 2}, \"tags\": [\"a\"], \"extra\": true}")
+	jsonComparator := comparator.NewComparator().StrictObjectCheck(false).PathsToIgnore("$.meta").
+		Recorder(recorder.NewDefaultRecorder()).Build()
+	recorderLog, err := jsonComparator.Compare(expected, actual)
+
+	var output bytes.Buffer
+	result := Result{Name: "customer", Err: err, Log: recorderLog, Expected: expected, Actual: actual}
+	if err := WriteMarkdown(&output, "", Suite{Results: []Result{result}}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkContains(t, output.String(), "```diff\n--- expected\n+++ actual\n@@ -3,7 +3,7 @@\n   \"meta\": {\n"+
+		"     \"version\": 1\n   },\n-  \"name\": \"Bruce\",\n+  \"name\": \"Dick\",\n   \"tags\": [\n     \"a\"\n   ]\n```")
+	for _, line := range []string{"-  \"id\"", "+  \"id\"", "\"extra\"", "\"version\": 2"} {
+		if strings.Contains(output.String(), line) {
+			t.Errorf("the diff must only contain the mismatches, found [%s]:\n%s", line, output.String())
+		}
+	}
+}
+
+func TestWriteMarkdownTruncation(t *testing.T) {
+	long := strings.Repeat("x", 500)
+	_, err := comparator.NewComparator().Build().Compare([]byte("{\"value\": \""+long+"\"}"), []byte("{\"value\": \"y\"}"))
+	logLines := "```\n" + strings.Repeat("line\n", 400)
+	result := Result{Name: "long", Err: errors.Join(err, errors.New("first\nsecond")), Log: logLines}
+
+	var output bytes.Buffer
+	if err := WriteMarkdown(&output, "", Suite{Results: []Result{result}}); err != nil {
+		t.Fatal(err)
+	}
+
+	markdown := output.String()
+	checkContains(t, markdown,
+		"| `$.value` | value mismatch | `\""+strings.Repeat("x", 78)+"…` | `\"y\"` |\n",
+		"- first\n",
+		"<summary>Recorder output</summary>\n\n````\n```\nline\n",
+		"line\n... [101] more line(s)\n````\n",
+	)
+	if strings.Contains(markdown, long) || strings.Contains(markdown, "second") {
+		t.Errorf("the values must be truncated:\n%s", markdown)
+	}
+}
+
+func TestWriteMarkdownPassed(t *testing.T) {
+	passed, _, _ := testResults(t)
+
+	var output bytes.Buffer
+	if err := WriteMarkdown(&output, "checks", Suite{Results: []Result{passed}}); err != nil {
+		t.Fatal(err)
+	}
+	checkReport(t, "### checks\n\n1 comparison(s) - 1 passed, 0 failed, 0 error(s)\n", output.String())
+}
+
+func TestCodeSpan(t *testing.T) {
+	for text, expected := range map[string]string{
+		"$.name":   "`$.name`",
+		"a|b":      "`a\\|b`",
+		"a`b":      "``a`b``",
+		"`a``":     "``` `a`` ```",
+		"\"Dick\"": "`\"Dick\"`",
+	} {
+		if actual := codeSpan(text); actual != expected {
+			t.Errorf("wrong code span for [%s]: [%s]", text, actual)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package report
+
+import (
+	"errors"
+	"github.com/go-clarum/clarum-json/comparator"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of one named comparison. Err is the error returned by the [comparator.Comparator],
+// nil if the JSONs match, and Log the output of its recorder.
+//
+// Expected & Actual optionally contain the compared JSONs, for the reports that show the documents.
+type Result struct {
+	Name     string
+	Err      error
+	Log      string
+	Duration time.Duration
+	Expected []byte
+	Actual   []byte
+}
+
+// Passed checks if the JSONs match.
+func (result Result) Passed() bool {
+	return result.Err == nil
+}
+
+// Failed checks if the comparison found mismatches. A result that did not pass without a mismatch, for example
+// because a JSON is invalid, is an error instead.
+func (result Result) Failed() bool {
+	return len(result.Mismatches()) > 0
+}
+
+// Mismatches returns the mismatches found by the comparison.
+func (result Result) Mismatches() []*comparator.Mismatch {
+	return comparator.Mismatches(result.Err)
+}
+
+// Errors returns the errors of the comparison that are not mismatches, like invalid JSON or exceeded limits.
+func (result Result) Errors() []error {
+	return otherErrors(result.Err)
+}
+
+func otherErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	if joinedErrors, ok := err.(interface{ Unwrap() []error }); ok {
+		var result []error
+		for _, joinedError := range joinedErrors.Unwrap() {
+			result = append(result, otherErrors(joinedError)...)
+		}
+		return result
+	}
+
+	var mismatch *comparator.Mismatch
+	if errors.As(err, &mismatch) {
+		return nil
+	}
+	return []error{err}
+}
+
+// Suite is a named group of results, like the files of a directory or the assertions of a test package.
+type Suite struct {
+	Name    string
+	Results []Result
+}
+
+// Counts returns the number of results that passed, failed & had an error.
+func (suite Suite) Counts() (passed int, failed int, errored int) {
+	for _, result := range suite.Results {
+		switch {
+		case result.Passed():
+			passed++
+		case result.Failed():
+			failed++
+		default:
+			errored++
+		}
+	}
+	return
+}
+
+// Duration returns the sum of the durations of the results.
+func (suite Suite) Duration() time.Duration {
+	var duration time.Duration
+	for _, result := range suite.Results {
+		duration += result.Duration
+	}
+	return duration
+}
+
+// Collector gathers results from concurrent comparisons, for example from tests that run in parallel,
+// so that they can be written into one report at the end. The suites are kept in the order they were created.
+type Collector struct {
+	mutex  sync.Mutex
+	suites []Suite
+}
+
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add adds the result to the suite with the given name.
+func (collector *Collector) Add(suiteName string, result Result) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	for i := range collector.suites {
+		if collector.suites[i].Name == suiteName {
+			collector.suites[i].Results = append(collector.suites[i].Results, result)
+			return
+		}
+	}
+	collector.suites = append(collector.suites, Suite{Name: suiteName, Results: []Result{result}})
+}
+
+// Suites returns a copy of the collected suites.
+func (collector *Collector) Suites() []Suite {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	suites := make([]Suite, len(collector.suites))
+	for i, suite := range collector.suites {
+		suites[i] = Suite{Name: suite.Name, Results: append([]Result(nil), suite.Results...)}
+	}
+	return suites
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-clarum/clarum-json/batch"
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/recorder"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestResult(t *testing.T) {
+	passed, failed, errored := testResults(t)
+
+	if !passed.Passed() || passed.Failed() || len(passed.Errors()) != 0 {
+		t.Errorf("the result must pass: %+v", passed)
+	}
+	if failed.Passed() || !failed.Failed() || len(failed.Mismatches()) != 1 || len(failed.Errors()) != 0 {
+		t.Errorf("the result must fail: %+v", failed)
+	}
+	if errored.Passed() || errored.Failed() || len(errored.Errors()) != 1 {
+		t.Errorf("the result must be an error: %+v", errored)
+	}
+
+	_, err := comparator.NewComparator().MaxErrors(1).Build().Compare([]byte("[1, 2]"), []byte("[3, 4]"))
+	limited := Result{Err: err}
+	if !limited.Failed() || len(limited.Mismatches()) != 1 || len(limited.Errors()) != 1 {
+		t.Errorf("the limit error must be separated from the mismatches: %v", err)
+	}
+}
+
+func TestSuite(t *testing.T) {
+	passed, failed, errored := testResults(t)
+	suite := Suite{Name: "customers", Results: []Result{passed, failed, failed, errored}}
+
+	passedCount, failedCount, erroredCount := suite.Counts()
+	if passedCount != 1 || failedCount != 2 || erroredCount != 1 {
+		t.Errorf("wrong counts: %d, %d, %d", passedCount, failedCount, erroredCount)
+	}
+	if suite.Duration() != 8*time.Millisecond {
+		t.Errorf("wrong duration: %s", suite.Duration())
+	}
+}
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector()
+
+	var wait sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			collector.Add(fmt.Sprintf("suite-%d", i%2), Result{Name: fmt.Sprintf("result-%d", i)})
+		}(i)
+	}
+	wait.Wait()
+
+	suites := collector.Suites()
+	if len(suites) != 2 || len(suites[0].Results) != 10 || len(suites[1].Results) != 10 {
+		t.Errorf("wrong suites: %+v", suites)
+	}
+
+	suites[0].Results[0].Name = "changed"
+	if collector.Suites()[0].Results[0].Name == "changed" {
+		t.Error("the suites must be copied")
+	}
+}
+
+func TestFromSummary(t *testing.T) {
+	expectedDir, actualDir := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(expectedDir, "1.json"), "{\"id\": 1}")
+	writeFile(t, filepath.Join(actualDir, "1.json"), "{\"id\": 2}")
+	writeFile(t, filepath.Join(expectedDir, "2.json"), "{}")
+	writeFile(t, filepath.Join(actualDir, "3.json"), "{}")
+
+	summary, err := batch.NewDirComparator().Build().Compare(context.Background(), expectedDir, actualDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suite := FromSummary("migration", summary)
+	if suite.Name != "migration" || len(suite.Results) != 3 {
+		t.Fatalf("wrong suite: %+v", suite)
+	}
+	if result := suite.Results[0]; result.Name != "1.json" || !result.Failed() {
+		t.Errorf("wrong result: %+v", result)
+	}
+	if result := suite.Results[1]; result.Name != "2.json" || result.Err != errMissingActual {
+		t.Errorf("wrong result: %+v", result)
+	}
+	if result := suite.Results[2]; result.Name != "3.json" || result.Err != errUnexpectedActual {
+		t.Errorf("wrong result: %+v", result)
+	}
+}
+
+// testResults returns a result that passed, one that failed and one with an error.
+func testResults(t *testing.T) (Result, Result, Result) {
+	var results []Result
+	for _, actual := range []string{"{\"name\": \"Bruce\"}", "{\"name\": \"Dick\"}", "{\"name\": "} {
+		jsonComparator := comparator.NewComparator().Recorder(recorder.NewDefaultRecorder()).Build()
+		recorderLog, err := jsonComparator.Compare([]byte("{\"name\": \"Bruce\"}"), []byte(actual))
+		results = append(results, Result{Name: fmt.Sprintf("result-%d", len(results)+1), Err: err, Log: recorderLog,
+			Duration: time.Duration(len(results)+1) * time.Millisecond})
+	}
+	return results[0], results[1], results[2]
+}
+
+func writeFile(t *testing.T, path string, content string) {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
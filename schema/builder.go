@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"github.com/go-clarum/clarum-json/internal"
+	"github.com/go-clarum/clarum-json/recorder"
+	"log/slog"
+)
+
+type options struct {
+	logger   *slog.Logger
+	recorder recorder.Recorder
+}
+
+type Builder struct {
+	options
+}
+
+// NewValidator is the builder initiator. Always use the builder to create a [Validator]
+// as this will set the default options.
+func NewValidator() *Builder {
+	return &Builder{
+		options{
+			logger:   slog.Default(),
+			recorder: internal.NewNoopRecorder(),
+		},
+	}
+}
+
+func (builder *Builder) Logger(logger *slog.Logger) *Builder {
+	builder.logger = logger
+	return builder
+}
+
+// Recorder to be used. The recorder receives the same calls as from the [comparator.Comparator],
+// with the actual JSON in place of both documents.
+// Default is the NoopRecorder.
+func (builder *Builder) Recorder(recorder recorder.Recorder) *Builder {
+	builder.recorder = recorder
+	return builder
+}
+
+func (builder *Builder) Build() *Validator {
+	return &Validator{options: builder.options}
+}
@@ -0,0 +1,705 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Draft is the URI of the JSON Schema dialect supported by the [Validator].
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// defaultBaseUri is the base URI of a schema without '$id', used to resolve its references.
+const defaultBaseUri = "clarum-json:///schema.json"
+
+// The keywords that contain a schema, an array of schemas or an object of schemas.
+var (
+	schemaKeywords = []string{"additionalProperties", "unevaluatedProperties", "items", "contains", "propertyNames",
+		"not", "if", "then", "else", "unevaluatedItems"}
+	schemaArrayKeywords  = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	schemaObjectKeywords = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+)
+
+var jsonTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true, "number": true, "string": true, "integer": true,
+}
+
+// node is a compiled schema or subschema.
+type node struct {
+	// location is the JSON Pointer of the schema in the document, as a URI fragment
+	location string
+	resource *resource
+	raw      map[string]any
+	// boolean is set for the schemas true & false, all other fields are empty then
+	boolean *bool
+
+	ref              *node
+	dynamicRef       *node
+	dynamicRefAnchor string
+
+	types      []string
+	enum       []any
+	hasConst   bool
+	constValue any
+
+	multipleOf       *number
+	maximum          *number
+	exclusiveMaximum *number
+	minimum          *number
+	exclusiveMinimum *number
+	maxLength        *int
+	minLength        *int
+	pattern          *regexp.Regexp
+
+	maxItems    *int
+	minItems    *int
+	uniqueItems bool
+	maxContains *int
+	minContains *int
+
+	maxProperties     *int
+	minProperties     *int
+	required          []string
+	dependentRequired map[string][]string
+
+	allOf            []*node
+	anyOf            []*node
+	oneOf            []*node
+	not              *node
+	ifSchema         *node
+	thenSchema       *node
+	elseSchema       *node
+	dependentSchemas map[string]*node
+
+	prefixItems      []*node
+	items            *node
+	contains         *node
+	unevaluatedItems *node
+
+	properties            map[string]*node
+	patternProperties     []patternProperty
+	additionalProperties  *node
+	propertyNames         *node
+	unevaluatedProperties *node
+}
+
+type patternProperty struct {
+	pattern *regexp.Regexp
+	schema  *node
+}
+
+func (node *node) isFalse() bool {
+	return node.boolean != nil && !*node.boolean
+}
+
+// number is a numeric keyword, with its exact value for the comparisons and its text for the messages.
+type number struct {
+	text  json.Number
+	value *big.Rat
+}
+
+// resource is a schema with its own base URI, the root schema or an embedded schema with '$id'.
+type resource struct {
+	uri string
+	// pointer is the location of the resource in the document
+	pointer        string
+	anchors        map[string]string
+	dynamicAnchors map[string]*node
+}
+
+type compiler struct {
+	document  any
+	resources map[string]*resource
+	// resourcePointers contains the resources by their location in the document
+	resourcePointers map[string]*resource
+	nodes            map[string]*node
+	dynamicAnchors   map[*resource]map[string]string
+}
+
+func compile(document any) (*node, error) {
+	compiler := &compiler{
+		document:         document,
+		resources:        make(map[string]*resource),
+		resourcePointers: make(map[string]*resource),
+		nodes:            make(map[string]*node),
+		dynamicAnchors:   make(map[*resource]map[string]string),
+	}
+	if err := compiler.scan(document, "", defaultBaseUri, nil); err != nil {
+		return nil, err
+	}
+
+	root, err := compiler.compile("")
+	if err != nil {
+		return nil, err
+	}
+
+	// the targets of '$dynamicRef' are only known during the validation, so all dynamic anchors are compiled
+	for resource, anchors := range compiler.dynamicAnchors {
+		for anchor, pointer := range anchors {
+			anchorNode, err := compiler.compile(pointer)
+			if err != nil {
+				return nil, err
+			}
+			resource.dynamicAnchors[anchor] = anchorNode
+		}
+	}
+	return root, nil
+}
+
+// scan registers the resources & anchors of the schema and its subschemas.
+func (compiler *compiler) scan(schema any, pointer string, baseUri string, current *resource) error {
+	schemaObject, isObject := schema.(map[string]any)
+	if !isObject {
+		if current == nil {
+			return compiler.addResource(baseUri, pointer)
+		}
+		return nil
+	}
+
+	// embedded resources can declare their own dialect, which must be the supported one as well
+	if dialect, exists := schemaObject["$schema"]; exists && strings.TrimSuffix(fmt.Sprint(dialect), "#") != Draft {
+		return fmt.Errorf("invalid schema - unsupported $schema [%v] - expected [%s]", dialect, Draft)
+	}
+
+	if id, exists := schemaObject["$id"]; exists || current == nil {
+		if exists {
+			idString, isString := id.(string)
+			if !isString {
+				return invalidSchema(pointer+"/$id", "must be a string")
+			}
+			resolved, err := resolveUri(baseUri, idString)
+			if err != nil {
+				return invalidSchema(pointer+"/$id", err.Error())
+			}
+			baseUri = resolved
+		}
+		if err := compiler.addResource(baseUri, pointer); err != nil {
+			return err
+		}
+		current = compiler.resourcePointers[pointer]
+	}
+
+	for _, keyword := range []string{"$anchor", "$dynamicAnchor"} {
+		if anchor, exists := schemaObject[keyword]; exists {
+			anchorString, isString := anchor.(string)
+			if !isString {
+				return invalidSchema(pointer+"/"+keyword, "must be a string")
+			}
+			current.anchors[anchorString] = pointer
+			if keyword == "$dynamicAnchor" {
+				compiler.dynamicAnchors[current][anchorString] = pointer
+			}
+		}
+	}
+
+	for _, keyword := range schemaKeywords {
+		if subschema, exists := schemaObject[keyword]; exists {
+			if err := compiler.scan(subschema, pointer+"/"+keyword, baseUri, current); err != nil {
+				return err
+			}
+		}
+	}
+	for _, keyword := range schemaArrayKeywords {
+		if subschemas, isArray := schemaObject[keyword].([]any); isArray {
+			for i, subschema := range subschemas {
+				if err := compiler.scan(subschema, fmt.Sprintf("%s/%s/%d", pointer, keyword, i), baseUri, current); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, keyword := range schemaObjectKeywords {
+		if subschemas, isObject := schemaObject[keyword].(map[string]any); isObject {
+			for name, subschema := range subschemas {
+				if err := compiler.scan(subschema, pointer+"/"+keyword+"/"+escapePointerToken(name), baseUri, current); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (compiler *compiler) addResource(uri string, pointer string) error {
+	if _, exists := compiler.resources[uri]; exists {
+		return invalidSchema(pointer, fmt.Sprintf("duplicate $id [%s]", uri))
+	}
+
+	newResource := &resource{uri: uri, pointer: pointer, anchors: make(map[string]string),
+		dynamicAnchors: make(map[string]*node)}
+	compiler.resources[uri] = newResource
+	compiler.resourcePointers[pointer] = newResource
+	compiler.dynamicAnchors[newResource] = make(map[string]string)
+	return nil
+}
+
+// resourceAt returns the resource that contains the location, which is the resource at the longest prefix.
+func (compiler *compiler) resourceAt(pointer string) *resource {
+	for prefix := pointer; ; {
+		if found, exists := compiler.resourcePointers[prefix]; exists {
+			return found
+		}
+		prefix = prefix[:max(strings.LastIndex(prefix, "/"), 0)]
+	}
+}
+
+// compile compiles the schema at the location. The nodes are cached before their keywords are compiled,
+// so that recursive references end at the node that is being compiled.
+func (compiler *compiler) compile(pointer string) (*node, error) {
+	if compiled, exists := compiler.nodes[pointer]; exists {
+		return compiled, nil
+	}
+
+	schema, found := evaluatePointer(compiler.document, pointer)
+	if !found {
+		return nil, invalidSchema(pointer, "no schema at this location")
+	}
+
+	compiled := &node{location: "#" + pointer, resource: compiler.resourceAt(pointer)}
+	compiler.nodes[pointer] = compiled
+
+	switch typedSchema := schema.(type) {
+	case bool:
+		compiled.boolean = &typedSchema
+		return compiled, nil
+	case map[string]any:
+		compiled.raw = typedSchema
+		return compiled, compiler.compileKeywords(compiled, pointer)
+	default:
+		return nil, invalidSchema(pointer, "must be an object or a boolean")
+	}
+}
+
+func (compiler *compiler) compileKeywords(compiled *node, pointer string) error {
+	keywords := keywordReader{compiler: compiler, pointer: pointer, values: compiled.raw}
+
+	compiled.ref, compiled.dynamicRef, compiled.dynamicRefAnchor = keywords.reference("$ref"),
+		keywords.reference("$dynamicRef"), keywords.dynamicAnchor(compiled.resource)
+
+	compiled.types = keywords.types()
+	compiled.enum = keywords.array("enum")
+	compiled.constValue, compiled.hasConst = compiled.raw["const"]
+
+	compiled.multipleOf = keywords.number("multipleOf", true)
+	compiled.maximum = keywords.number("maximum", false)
+	compiled.exclusiveMaximum = keywords.number("exclusiveMaximum", false)
+	compiled.minimum = keywords.number("minimum", false)
+	compiled.exclusiveMinimum = keywords.number("exclusiveMinimum", false)
+	compiled.maxLength = keywords.count("maxLength")
+	compiled.minLength = keywords.count("minLength")
+	compiled.pattern = keywords.pattern("pattern")
+
+	compiled.maxItems = keywords.count("maxItems")
+	compiled.minItems = keywords.count("minItems")
+	compiled.uniqueItems = keywords.boolean("uniqueItems")
+	compiled.maxContains = keywords.count("maxContains")
+	compiled.minContains = keywords.count("minContains")
+
+	compiled.maxProperties = keywords.count("maxProperties")
+	compiled.minProperties = keywords.count("minProperties")
+	compiled.required = keywords.strings("required", "required")
+	compiled.dependentRequired = keywords.dependentRequired()
+
+	compiled.allOf = keywords.schemaArray("allOf")
+	compiled.anyOf = keywords.schemaArray("anyOf")
+	compiled.oneOf = keywords.schemaArray("oneOf")
+	compiled.not = keywords.schema("not")
+	compiled.ifSchema = keywords.schema("if")
+	compiled.thenSchema = keywords.schema("then")
+	compiled.elseSchema = keywords.schema("else")
+	compiled.dependentSchemas = keywords.schemaObject("dependentSchemas")
+
+	compiled.prefixItems = keywords.schemaArray("prefixItems")
+	compiled.items = keywords.schema("items")
+	compiled.contains = keywords.schema("contains")
+	compiled.unevaluatedItems = keywords.schema("unevaluatedItems")
+
+	compiled.properties = keywords.schemaObject("properties")
+	compiled.patternProperties = keywords.patternProperties()
+	compiled.additionalProperties = keywords.schema("additionalProperties")
+	compiled.propertyNames = keywords.schema("propertyNames")
+	compiled.unevaluatedProperties = keywords.schema("unevaluatedProperties")
+
+	return keywords.err
+}
+
+// keywordReader reads the keywords of a schema object. The first invalid keyword is kept as error,
+// so that the keywords can be read one after the other.
+type keywordReader struct {
+	compiler *compiler
+	pointer  string
+	values   map[string]any
+	err      error
+}
+
+func (keywords *keywordReader) fail(keyword string, message string) {
+	if keywords.err == nil {
+		keywords.err = invalidSchema(keywords.pointer+"/"+keyword, message)
+	}
+}
+
+func (keywords *keywordReader) reference(keyword string) *node {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	reference, isString := value.(string)
+	if !isString {
+		keywords.fail(keyword, "must be a string")
+		return nil
+	}
+
+	uri, err := resolveUri(keywords.compiler.resourceAt(keywords.pointer).uri, reference)
+	if err != nil {
+		keywords.fail(keyword, err.Error())
+		return nil
+	}
+
+	resourceUri, fragment, _ := strings.Cut(uri, "#")
+	target, exists := keywords.compiler.resources[resourceUri]
+	if !exists {
+		keywords.fail(keyword, fmt.Sprintf("unable to resolve [%s] - only references within the schema are supported", reference))
+		return nil
+	}
+
+	pointer := target.pointer
+	if strings.HasPrefix(fragment, "/") || fragment == "" {
+		unescaped, err := url.PathUnescape(fragment)
+		if err != nil {
+			keywords.fail(keyword, fmt.Sprintf("invalid JSON Pointer [%s]", fragment))
+			return nil
+		}
+		pointer += unescaped
+	} else if anchorPointer, exists := target.anchors[fragment]; exists {
+		pointer = anchorPointer
+	} else {
+		keywords.fail(keyword, fmt.Sprintf("unable to resolve [%s] - unknown anchor [%s]", reference, fragment))
+		return nil
+	}
+
+	referenced, err := keywords.compiler.compile(pointer)
+	if err != nil {
+		keywords.fail(keyword, fmt.Sprintf("unable to resolve [%s] - %s", reference, err))
+		return nil
+	}
+	return referenced
+}
+
+// dynamicAnchor returns the anchor of '$dynamicRef', if it points to a '$dynamicAnchor'. Only then the reference
+// is resolved dynamically, otherwise it behaves like '$ref'.
+func (keywords *keywordReader) dynamicAnchor(current *resource) string {
+	reference, isString := keywords.values["$dynamicRef"].(string)
+	if !isString {
+		return ""
+	}
+
+	_, anchor, _ := strings.Cut(reference, "#")
+	if anchor == "" || strings.HasPrefix(anchor, "/") {
+		return ""
+	}
+
+	uri, err := resolveUri(current.uri, reference)
+	if err != nil {
+		return ""
+	}
+	resourceUri, _, _ := strings.Cut(uri, "#")
+	target, exists := keywords.compiler.resources[resourceUri]
+	if !exists {
+		return ""
+	}
+	if _, isDynamic := keywords.compiler.dynamicAnchors[target][anchor]; !isDynamic {
+		return ""
+	}
+	return anchor
+}
+
+func (keywords *keywordReader) types() []string {
+	value, exists := keywords.values["type"]
+	if !exists {
+		return nil
+	}
+
+	var types []string
+	if typeString, isString := value.(string); isString {
+		types = []string{typeString}
+	} else {
+		types = keywords.strings("type", "type")
+	}
+
+	for _, jsonType := range types {
+		if !jsonTypes[jsonType] {
+			keywords.fail("type", fmt.Sprintf("unknown type [%s]", jsonType))
+			return nil
+		}
+	}
+	return types
+}
+
+func (keywords *keywordReader) array(keyword string) []any {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	array, isArray := value.([]any)
+	if !isArray {
+		keywords.fail(keyword, "must be an array")
+	}
+	return array
+}
+
+func (keywords *keywordReader) strings(keyword string, location string) []string {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	array, isArray := value.([]any)
+	if !isArray {
+		keywords.fail(location, "must be an array of strings")
+		return nil
+	}
+
+	result := make([]string, 0, len(array))
+	for _, item := range array {
+		itemString, isString := item.(string)
+		if !isString {
+			keywords.fail(location, "must be an array of strings")
+			return nil
+		}
+		result = append(result, itemString)
+	}
+	return result
+}
+
+func (keywords *keywordReader) number(keyword string, positive bool) *number {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	text, isNumber := value.(json.Number)
+	if !isNumber || (positive && exactValue(text).Sign() <= 0) {
+		if positive {
+			keywords.fail(keyword, "must be a number greater than 0")
+		} else {
+			keywords.fail(keyword, "must be a number")
+		}
+		return nil
+	}
+	return &number{text, exactValue(text)}
+}
+
+func (keywords *keywordReader) count(keyword string) *int {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	text, isNumber := value.(json.Number)
+	if !isNumber {
+		keywords.fail(keyword, "must be a non-negative integer")
+		return nil
+	}
+	number := exactValue(text)
+	if !number.IsInt() || number.Sign() < 0 || number.Num().Cmp(big.NewInt(math.MaxInt32)) > 0 {
+		keywords.fail(keyword, "must be a non-negative integer")
+		return nil
+	}
+	count := int(number.Num().Int64())
+	return &count
+}
+
+func (keywords *keywordReader) boolean(keyword string) bool {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return false
+	}
+
+	boolean, isBool := value.(bool)
+	if !isBool {
+		keywords.fail(keyword, "must be a boolean")
+	}
+	return boolean
+}
+
+func (keywords *keywordReader) pattern(keyword string) *regexp.Regexp {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	pattern, isString := value.(string)
+	if !isString {
+		keywords.fail(keyword, "must be a string")
+		return nil
+	}
+
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		keywords.fail(keyword, fmt.Sprintf("invalid pattern [%s] - %s", pattern, err))
+		return nil
+	}
+	return compiled
+}
+
+func (keywords *keywordReader) dependentRequired() map[string][]string {
+	value, exists := keywords.values["dependentRequired"]
+	if !exists {
+		return nil
+	}
+
+	object, isObject := value.(map[string]any)
+	if !isObject {
+		keywords.fail("dependentRequired", "must be an object")
+		return nil
+	}
+
+	result := make(map[string][]string, len(object))
+	dependencies := keywordReader{compiler: keywords.compiler, pointer: keywords.pointer + "/dependentRequired", values: object}
+	for name := range object {
+		result[name] = dependencies.strings(name, escapePointerToken(name))
+	}
+	if dependencies.err != nil && keywords.err == nil {
+		keywords.err = dependencies.err
+	}
+	return result
+}
+
+func (keywords *keywordReader) schema(keyword string) *node {
+	if _, exists := keywords.values[keyword]; !exists {
+		return nil
+	}
+
+	compiled, err := keywords.compiler.compile(keywords.pointer + "/" + keyword)
+	if err != nil && keywords.err == nil {
+		keywords.err = err
+	}
+	return compiled
+}
+
+func (keywords *keywordReader) schemaArray(keyword string) []*node {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	array, isArray := value.([]any)
+	if !isArray || len(array) == 0 {
+		keywords.fail(keyword, "must be a non-empty array of schemas")
+		return nil
+	}
+
+	result := make([]*node, 0, len(array))
+	for i := range array {
+		compiled, err := keywords.compiler.compile(fmt.Sprintf("%s/%s/%d", keywords.pointer, keyword, i))
+		if err != nil && keywords.err == nil {
+			keywords.err = err
+		}
+		result = append(result, compiled)
+	}
+	return result
+}
+
+func (keywords *keywordReader) schemaObject(keyword string) map[string]*node {
+	value, exists := keywords.values[keyword]
+	if !exists {
+		return nil
+	}
+
+	object, isObject := value.(map[string]any)
+	if !isObject {
+		keywords.fail(keyword, "must be an object of schemas")
+		return nil
+	}
+
+	result := make(map[string]*node, len(object))
+	for name := range object {
+		compiled, err := keywords.compiler.compile(keywords.pointer + "/" + keyword + "/" + escapePointerToken(name))
+		if err != nil && keywords.err == nil {
+			keywords.err = err
+		}
+		result[name] = compiled
+	}
+	return result
+}
+
+func (keywords *keywordReader) patternProperties() []patternProperty {
+	schemas := keywords.schemaObject("patternProperties")
+
+	patterns := make([]string, 0, len(schemas))
+	for pattern := range schemas {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	result := make([]patternProperty, 0, len(patterns))
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			keywords.fail("patternProperties/"+escapePointerToken(pattern), fmt.Sprintf("invalid pattern - %s", err))
+			return nil
+		}
+		result = append(result, patternProperty{compiled, schemas[pattern]})
+	}
+	return result
+}
+
+func resolveUri(base string, reference string) (string, error) {
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid URI [%s]", base)
+	}
+	referenceUrl, err := url.Parse(reference)
+	if err != nil {
+		return "", fmt.Errorf("invalid URI [%s]", reference)
+	}
+
+	return baseUrl.ResolveReference(referenceUrl).String(), nil
+}
+
+// evaluatePointer returns the value at the JSON Pointer (RFC 6901) in the document.
+func evaluatePointer(document any, pointer string) (any, bool) {
+	if pointer == "" {
+		return document, true
+	}
+
+	current := document
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch typedValue := current.(type) {
+		case map[string]any:
+			value, exists := typedValue[token]
+			if !exists {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(typedValue) {
+				return nil, false
+			}
+			current = typedValue[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+var pointerTokenEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapePointerToken(token string) string {
+	return pointerTokenEscaper.Replace(token)
+}
+
+func invalidSchema(pointer string, message string) error {
+	return fmt.Errorf("invalid schema - [#%s] %s", pointer, message)
+}
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReferences(t *testing.T) {
+	testCases := []struct {
+		name           string
+		schema         string
+		actual         string
+		expectedErrors []string
+	}{
+		{"pointer", `{"properties": {"home": {"$ref": "#/$defs/address"}},
+			"$defs": {"address": {"required": ["city"]}}}`, `{"home": {}}`,
+			[]string{"[$.home.city] - field is missing - schema [#/$defs/address/required]"}},
+		{"escaped pointer", `{"$ref": "#/$defs/a~1b%25", "$defs": {"a/b%": {"type": "string"}}}`, `1`,
+			[]string{"[$] - type mismatch - expected [string] but found [number] - schema [#/$defs/a~1b%/type]"}},
+		{"anchor", `{"$ref": "#name", "$defs": {"name": {"$anchor": "name", "minLength": 2}}}`, `"a"`,
+			[]string{"[$] - value mismatch - expected at least [2] character(s) but received [1] - schema [#/$defs/name/minLength]"}},
+		{"recursion", `{"properties": {"name": {"type": "string"}, "children": {"items": {"$ref": "#"}}}}`,
+			`{"name": "Bruce", "children": [{"name": "Dick", "children": [{"name": 1}]}]}`,
+			[]string{"[$.children[0].children[0].name] - type mismatch - expected [string] but found [number] - schema [#/properties/name/type]"}},
+		{"embedded resource", `{"$id": "https://example.com/customer.json", "properties": {"home": {"$ref": "address.json"}},
+			"$defs": {"address": {"$id": "address.json", "properties": {"city": {"$ref": "#/$defs/city"}},
+			"$defs": {"city": {"type": "string"}}}}}`, `{"home": {"city": 1}}`,
+			[]string{"[$.home.city] - type mismatch - expected [string] but found [number] - schema [#/$defs/address/$defs/city/type]"}},
+		{"embedded anchor", `{"$ref": "other.json#zip", "$defs": {"other": {"$id": "other.json",
+			"$defs": {"zip": {"$anchor": "zip", "pattern": "^[0-9]+$"}}}}}`, `"ab"`,
+			[]string{"[$] - value mismatch - expected to match [^[0-9]+$] but received [ab] - schema [#/$defs/other/$defs/zip/pattern]"}},
+		{"dynamic reference", `{"$id": "https://example.com/strict-tree", "$dynamicAnchor": "node",
+			"$ref": "tree", "unevaluatedProperties": false,
+			"$defs": {"tree": {"$id": "tree", "$dynamicAnchor": "node", "type": "object",
+			"properties": {"data": true, "children": {"type": "array", "items": {"$dynamicRef": "#node"}}}}}}`,
+			`{"children": [{"daat": 1}]}`,
+			[]string{"[$.children[0].daat] - unexpected field - schema [#/unevaluatedProperties]"}},
+		{"dynamic reference without a dynamic anchor", `{"$dynamicRef": "#/$defs/a", "$defs": {"a": {"type": "string"}}}`,
+			`1`, []string{"[$] - type mismatch - expected [string] but found [number] - schema [#/$defs/a/type]"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewValidator().Build().Validate([]byte(testCase.schema), []byte(testCase.actual))
+			checkErrors(t, err, testCase.expectedErrors)
+		})
+	}
+}
+
+func TestInvalidSchema(t *testing.T) {
+	for schema, expectedError := range map[string]string{
+		`{"type": `:                              "unable to parse schema - error [unexpected end of JSON input]",
+		`1`:                                      "invalid schema - [#] must be an object or a boolean",
+		`{"type": "text"}`:                       "invalid schema - [#/type] unknown type [text]",
+		`{"minLength": -1}`:                      "invalid schema - [#/minLength] must be a non-negative integer",
+		`{"multipleOf": 0}`:                      "invalid schema - [#/multipleOf] must be a number greater than 0",
+		`{"pattern": "("}`:                       "invalid schema - [#/pattern] invalid pattern [(] - error parsing regexp: missing closing ): `(`",
+		`{"required": [1]}`:                      "invalid schema - [#/required] must be an array of strings",
+		`{"allOf": []}`:                          "invalid schema - [#/allOf] must be a non-empty array of schemas",
+		`{"properties": {"a": 1}}`:               "invalid schema - [#/properties/a] must be an object or a boolean",
+		`{"$ref": "#/$defs/missing"}`:            "invalid schema - [#/$ref] unable to resolve [#/$defs/missing] - invalid schema - [#/$defs/missing] no schema at this location",
+		`{"$ref": "#unknown"}`:                   "invalid schema - [#/$ref] unable to resolve [#unknown] - unknown anchor [unknown]",
+		`{"$ref": "https://example.com/a.json"}`: "invalid schema - [#/$ref] unable to resolve [https://example.com/a.json] - only references within the schema are supported",
+		`{"$schema": "http://json-schema.org/draft-07/schema#"}`:                                   "invalid schema - unsupported $schema [http://json-schema.org/draft-07/schema#] - expected [https://json-schema.org/draft/2020-12/schema]",
+		`{"$defs": {"a": {"$id": "a.json", "$schema": "http://json-schema.org/draft-07/schema"}}}`: "invalid schema - unsupported $schema [http://json-schema.org/draft-07/schema] - expected [https://json-schema.org/draft/2020-12/schema]",
+		`{"maximum": 1e500}`: "unable to parse schema - error [number [1e500] is out of range]",
+		`{"$defs": {"a": {"$id": "a.json"}, "b": {"$id": "a.json"}}}`: "invalid schema - [#/$defs/",
+	} {
+		_, err := NewValidator().Build().Compile([]byte(schema))
+		if err == nil || !strings.HasPrefix(err.Error(), expectedError) {
+			t.Errorf("wrong error for schema [%s]: %v", schema, err)
+		}
+	}
+}
+
+func TestEndlessReference(t *testing.T) {
+	_, err := NewValidator().Build().Validate([]byte(`{"$ref": "#/$defs/a", "$defs": {"a": {"$ref": "#"}}}`), []byte(`1`))
+
+	if err == nil || err.Error() != "schema evaluation entered an endless loop at [#] - the schema references "+
+		"itself without going deeper into the JSON at [$]" {
+		t.Errorf("expected an endless loop error: %v", err)
+	}
+}
+
+func TestDeepRecursiveReference(t *testing.T) {
+	schema := []byte(`{"type": "array", "items": {"$ref": "#"}}`)
+	actual := []byte(strings.Repeat("[", 300) + strings.Repeat("]", 300))
+
+	if _, err := NewValidator().Build().Validate(schema, actual); err != nil {
+		t.Errorf("no errors expected: %v", err)
+	}
+}
@@ -0,0 +1,698 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxExponent is the largest exponent of the numbers that can be compared exactly.
+const maxExponent = 400
+
+// violation is a keyword of the schema that the actual JSON does not satisfy.
+type violation struct {
+	kind    comparator.MismatchKind
+	path    string
+	pointer string
+	// parentPointer is set for missing fields, which are recorded with the object that misses them
+	parentPointer string
+	field         string
+	// location is the location of the keyword in the schema, as a URI fragment
+	location string
+	expected any
+	actual   any
+	message  string
+}
+
+func (violation violation) mismatch() *comparator.Mismatch {
+	return comparator.NewMismatch(violation.kind, violation.path, violation.pointer, violation.expected,
+		violation.actual, fmt.Sprintf("%s - schema [%s]", violation.message, violation.location))
+}
+
+// result is the outcome of a schema applied to one value. Besides the violations, it contains the annotations
+// needed by 'unevaluatedProperties' & 'unevaluatedItems': the fields & items evaluated by the schema.
+type result struct {
+	violations    []violation
+	properties    map[string]bool
+	items         int
+	allItems      bool
+	containsItems map[int]bool
+}
+
+func (result result) valid() bool {
+	return len(result.violations) == 0
+}
+
+// merge adds the result of a subschema the value must satisfy, like the ones of 'allOf' or '$ref'. Its annotations
+// are kept even if it failed: the value is invalid either way, and dropping them would report every field
+// of the subschema as unevaluated on top of the actual violation.
+func (result *result) merge(other result) {
+	result.violations = append(result.violations, other.violations...)
+	result.mergeAnnotations(other)
+}
+
+func (result *result) mergeAnnotations(other result) {
+	for property := range other.properties {
+		result.addProperty(property)
+	}
+	for item := range other.containsItems {
+		result.addContainsItem(item)
+	}
+	result.items = max(result.items, other.items)
+	result.allItems = result.allItems || other.allItems
+}
+
+func (result *result) addProperty(property string) {
+	if result.properties == nil {
+		result.properties = make(map[string]bool)
+	}
+	result.properties[property] = true
+}
+
+func (result *result) addContainsItem(item int) {
+	if result.containsItems == nil {
+		result.containsItems = make(map[int]bool)
+	}
+	result.containsItems[item] = true
+}
+
+// fail adds a violation of the keyword of the schema at the location of the value.
+func (result *result) fail(schema *node, keyword string, kind comparator.MismatchKind, path string, pointer string,
+	expected any, actual any, format string, a ...any) {
+	result.violations = append(result.violations, violation{kind: kind, path: path, pointer: pointer,
+		location: schema.location + "/" + keyword, expected: expected, actual: actual, message: fmt.Sprintf(format, a...)})
+}
+
+// evaluator applies a compiled schema to a value. The first error that stops the evaluation is kept.
+type evaluator struct {
+	// active contains the schemas being applied, with the location of their value, to detect schemas that
+	// reference themselves without going deeper into the actual JSON
+	active map[evaluationKey]bool
+	err    error
+}
+
+type evaluationKey struct {
+	schema  *node
+	pointer string
+}
+
+// evaluate applies the schema to the value at the path. The scope contains the resources entered so far,
+// from the outermost one, to resolve '$dynamicRef'.
+func (evaluator *evaluator) evaluate(schema *node, value any, path string, pointer string, scope []*resource) result {
+	var result result
+	if evaluator.err != nil {
+		return result
+	}
+
+	current := evaluationKey{schema, pointer}
+	if evaluator.active[current] {
+		evaluator.err = fmt.Errorf("schema evaluation entered an endless loop at [%s] - the schema references itself "+
+			"without going deeper into the JSON at [%s]", schema.location, path)
+		return result
+	}
+	if evaluator.active == nil {
+		evaluator.active = make(map[evaluationKey]bool)
+	}
+	evaluator.active[current] = true
+	defer delete(evaluator.active, current)
+
+	if schema.boolean != nil {
+		if !*schema.boolean {
+			result.violations = append(result.violations, violation{kind: comparator.ValueMismatch, path: path,
+				pointer: pointer, location: schema.location, actual: value, message: "value is not allowed"})
+		}
+		return result
+	}
+
+	if len(scope) == 0 || scope[len(scope)-1] != schema.resource {
+		scope = append(scope[:len(scope):len(scope)], schema.resource)
+	}
+
+	if schema.ref != nil {
+		result.merge(evaluator.evaluate(schema.ref, value, path, pointer, scope))
+	}
+	if schema.dynamicRef != nil {
+		result.merge(evaluator.evaluate(dynamicTarget(schema, scope), value, path, pointer, scope))
+	}
+
+	checkType(schema, value, path, pointer, &result)
+	checkValue(schema, value, path, pointer, &result)
+
+	switch typedValue := value.(type) {
+	case json.Number:
+		checkNumber(schema, typedValue, path, pointer, &result)
+	case string:
+		checkString(schema, typedValue, path, pointer, &result)
+	case []any:
+		evaluator.evaluateArray(schema, typedValue, path, pointer, scope, &result)
+	case map[string]any:
+		evaluator.evaluateObject(schema, typedValue, path, pointer, scope, &result)
+	}
+
+	evaluator.evaluateApplicators(schema, value, path, pointer, scope, &result)
+
+	// the unevaluated keywords depend on the annotations of all other keywords, so they come last
+	switch typedValue := value.(type) {
+	case []any:
+		evaluator.evaluateUnevaluatedItems(schema, typedValue, path, pointer, scope, &result)
+	case map[string]any:
+		evaluator.evaluateUnevaluatedProperties(schema, typedValue, path, pointer, scope, &result)
+	}
+	return result
+}
+
+// dynamicTarget returns the outermost schema of the scope with the '$dynamicAnchor' of the '$dynamicRef'.
+func dynamicTarget(schema *node, scope []*resource) *node {
+	if schema.dynamicRefAnchor != "" {
+		for _, scopeResource := range scope {
+			if target, exists := scopeResource.dynamicAnchors[schema.dynamicRefAnchor]; exists {
+				return target
+			}
+		}
+	}
+	return schema.dynamicRef
+}
+
+func checkType(schema *node, value any, path string, pointer string, result *result) {
+	if len(schema.types) == 0 {
+		return
+	}
+
+	for _, jsonType := range schema.types {
+		if hasType(value, jsonType) {
+			return
+		}
+	}
+	result.fail(schema, "type", comparator.TypeMismatch, path, pointer, schema.raw["type"], value,
+		"type mismatch - expected [%s] but found [%s]", strings.Join(schema.types, ", "), typeOf(value))
+}
+
+func checkValue(schema *node, value any, path string, pointer string, result *result) {
+	if schema.hasConst && !equal(schema.constValue, value) {
+		result.fail(schema, "const", comparator.ValueMismatch, path, pointer, schema.constValue, value,
+			"value mismatch - expected [%s] but received [%s]", formatValue(schema.constValue), formatValue(value))
+	}
+
+	if schema.enum != nil {
+		for _, allowed := range schema.enum {
+			if equal(allowed, value) {
+				return
+			}
+		}
+
+		allowedValues := make([]string, 0, len(schema.enum))
+		for _, allowed := range schema.enum {
+			allowedValues = append(allowedValues, formatValue(allowed))
+		}
+		result.fail(schema, "enum", comparator.ValueMismatch, path, pointer, schema.enum, value,
+			"value mismatch - expected one of [%s] but received [%s]", strings.Join(allowedValues, ", "), formatValue(value))
+	}
+}
+
+func checkNumber(schema *node, value json.Number, path string, pointer string, result *result) {
+	exact := exactValue(value)
+	if schema.multipleOf != nil && !isMultiple(exact, schema.multipleOf.value) {
+		result.fail(schema, "multipleOf", comparator.ValueMismatch, path, pointer, schema.multipleOf.text, value,
+			"value mismatch - expected a multiple of [%s] but received [%s]", schema.multipleOf.text, value)
+	}
+
+	limits := []struct {
+		keyword  string
+		limit    *number
+		operator string
+		// valid checks the result of comparing the value with the limit
+		valid func(comparison int) bool
+	}{
+		{"maximum", schema.maximum, "<=", func(comparison int) bool { return comparison <= 0 }},
+		{"exclusiveMaximum", schema.exclusiveMaximum, "<", func(comparison int) bool { return comparison < 0 }},
+		{"minimum", schema.minimum, ">=", func(comparison int) bool { return comparison >= 0 }},
+		{"exclusiveMinimum", schema.exclusiveMinimum, ">", func(comparison int) bool { return comparison > 0 }},
+	}
+	for _, limit := range limits {
+		if limit.limit != nil && !limit.valid(exact.Cmp(limit.limit.value)) {
+			result.fail(schema, limit.keyword, comparator.ValueMismatch, path, pointer, limit.limit.text, value,
+				"value mismatch - expected a value %s [%s] but received [%s]", limit.operator, limit.limit.text, value)
+		}
+	}
+}
+
+func checkString(schema *node, value string, path string, pointer string, result *result) {
+	length := utf8.RuneCountInString(value)
+	if schema.maxLength != nil && length > *schema.maxLength {
+		result.fail(schema, "maxLength", comparator.ValueMismatch, path, pointer, *schema.maxLength, value,
+			"value mismatch - expected at most [%d] character(s) but received [%d]", *schema.maxLength, length)
+	}
+	if schema.minLength != nil && length < *schema.minLength {
+		result.fail(schema, "minLength", comparator.ValueMismatch, path, pointer, *schema.minLength, value,
+			"value mismatch - expected at least [%d] character(s) but received [%d]", *schema.minLength, length)
+	}
+	if schema.pattern != nil && !schema.pattern.MatchString(value) {
+		result.fail(schema, "pattern", comparator.ValueMismatch, path, pointer, schema.pattern.String(), value,
+			"value mismatch - expected to match [%s] but received [%s]", schema.pattern, value)
+	}
+}
+
+func (evaluator *evaluator) evaluateArray(schema *node, array []any, path string, pointer string, scope []*resource,
+	result *result) {
+	if schema.maxItems != nil && len(array) > *schema.maxItems {
+		result.fail(schema, "maxItems", comparator.ArraySizeMismatch, path, pointer, *schema.maxItems, len(array),
+			"array size mismatch - expected at most [%d] item(s) but received [%d]", *schema.maxItems, len(array))
+	}
+	if schema.minItems != nil && len(array) < *schema.minItems {
+		result.fail(schema, "minItems", comparator.ArraySizeMismatch, path, pointer, *schema.minItems, len(array),
+			"array size mismatch - expected at least [%d] item(s) but received [%d]", *schema.minItems, len(array))
+	}
+	if schema.uniqueItems {
+		checkUniqueItems(schema, array, path, pointer, result)
+	}
+
+	for i, itemSchema := range schema.prefixItems {
+		if i >= len(array) {
+			break
+		}
+		evaluator.evaluateItem(itemSchema, array, i, path, pointer, scope, result)
+		result.items = i + 1
+	}
+	if schema.items != nil {
+		for i := len(schema.prefixItems); i < len(array); i++ {
+			evaluator.evaluateItem(schema.items, array, i, path, pointer, scope, result)
+		}
+		result.allItems = true
+	}
+
+	if schema.contains != nil {
+		evaluator.evaluateContains(schema, array, path, pointer, scope, result)
+	}
+}
+
+func checkUniqueItems(schema *node, array []any, path string, pointer string, result *result) {
+	for i := range array {
+		for j := i + 1; j < len(array); j++ {
+			if equal(array[i], array[j]) {
+				result.fail(schema, "uniqueItems", comparator.ValueMismatch, path, pointer, true, array,
+					"value mismatch - expected unique items but items [%d] and [%d] are equal", i, j)
+				return
+			}
+		}
+	}
+}
+
+// evaluateItem applies the schema to an item of the array. An item that is not allowed at all is reported
+// as an unexpected item of the array.
+func (evaluator *evaluator) evaluateItem(itemSchema *node, array []any, index int, path string, pointer string,
+	scope []*resource, result *result) {
+	itemPath, itemPointer := jsonpath.GetArrayIndexPath(path, index), jsonpath.GetArrayIndexPointer(pointer, index)
+
+	if itemSchema.isFalse() {
+		result.violations = append(result.violations, violation{kind: comparator.ArraySizeMismatch, path: itemPath,
+			pointer: itemPointer, location: itemSchema.location, actual: array[index], message: "unexpected item"})
+		return
+	}
+	itemResult := evaluator.evaluate(itemSchema, array[index], itemPath, itemPointer, scope)
+	result.violations = append(result.violations, itemResult.violations...)
+}
+
+func (evaluator *evaluator) evaluateContains(schema *node, array []any, path string, pointer string,
+	scope []*resource, result *result) {
+	matching := 0
+	for i, item := range array {
+		itemResult := evaluator.evaluate(schema.contains, item, jsonpath.GetArrayIndexPath(path, i),
+			jsonpath.GetArrayIndexPointer(pointer, i), scope)
+		if itemResult.valid() {
+			matching++
+			result.addContainsItem(i)
+		}
+	}
+
+	minimum, keyword := 1, "contains"
+	if schema.minContains != nil {
+		minimum, keyword = *schema.minContains, "minContains"
+	}
+	if matching < minimum {
+		result.fail(schema, keyword, comparator.ValueMismatch, path, pointer, minimum, matching,
+			"value mismatch - expected at least [%d] item(s) matching [contains] but received [%d]", minimum, matching)
+	}
+	if schema.maxContains != nil && matching > *schema.maxContains {
+		result.fail(schema, "maxContains", comparator.ValueMismatch, path, pointer, *schema.maxContains, matching,
+			"value mismatch - expected at most [%d] item(s) matching [contains] but received [%d]",
+			*schema.maxContains, matching)
+	}
+}
+
+func (evaluator *evaluator) evaluateObject(schema *node, object map[string]any, path string, pointer string,
+	scope []*resource, result *result) {
+	if schema.maxProperties != nil && len(object) > *schema.maxProperties {
+		result.fail(schema, "maxProperties", comparator.FieldCountMismatch, path, pointer, *schema.maxProperties,
+			len(object), "field count mismatch - expected at most [%d] field(s) but received [%d]",
+			*schema.maxProperties, len(object))
+	}
+	if schema.minProperties != nil && len(object) < *schema.minProperties {
+		result.fail(schema, "minProperties", comparator.FieldCountMismatch, path, pointer, *schema.minProperties,
+			len(object), "field count mismatch - expected at least [%d] field(s) but received [%d]",
+			*schema.minProperties, len(object))
+	}
+
+	for _, field := range schema.required {
+		if _, exists := object[field]; !exists {
+			addMissingField(schema, "required", field, path, pointer, "field is missing", result)
+		}
+	}
+	for _, field := range sortedKeys(schema.dependentRequired) {
+		if _, exists := object[field]; !exists {
+			continue
+		}
+		for _, dependency := range schema.dependentRequired[field] {
+			if _, exists := object[dependency]; !exists {
+				addMissingField(schema, "dependentRequired/"+escapePointerToken(field), dependency, path, pointer,
+					fmt.Sprintf("field is missing - required by [%s]", field), result)
+			}
+		}
+	}
+
+	for _, field := range sortedKeys(object) {
+		fieldPath, fieldPointer := jsonpath.GetObjectChildPath(path, field), jsonpath.GetObjectChildPointer(pointer, field)
+
+		evaluated := false
+		if fieldSchema, exists := schema.properties[field]; exists {
+			evaluator.evaluateField(fieldSchema, object[field], fieldPath, fieldPointer, scope, result)
+			evaluated = true
+		}
+		for _, patternProperty := range schema.patternProperties {
+			if patternProperty.pattern.MatchString(field) {
+				evaluator.evaluateField(patternProperty.schema, object[field], fieldPath, fieldPointer, scope, result)
+				evaluated = true
+			}
+		}
+		if !evaluated && schema.additionalProperties != nil {
+			evaluator.evaluateField(schema.additionalProperties, object[field], fieldPath, fieldPointer, scope, result)
+			evaluated = true
+		}
+		if evaluated {
+			result.addProperty(field)
+		}
+
+		if schema.propertyNames != nil {
+			nameResult := evaluator.evaluate(schema.propertyNames, field, fieldPath, fieldPointer, scope)
+			if !nameResult.valid() {
+				result.fail(schema, "propertyNames", comparator.UnexpectedField, fieldPath, fieldPointer, nil,
+					object[field], "unexpected field - invalid name - %s", nameResult.violations[0].message)
+			}
+		}
+	}
+}
+
+func addMissingField(schema *node, keyword string, field string, path string, pointer string, message string,
+	result *result) {
+	result.violations = append(result.violations, violation{kind: comparator.MissingField,
+		path: jsonpath.GetObjectChildPath(path, field), pointer: jsonpath.GetObjectChildPointer(pointer, field),
+		parentPointer: pointer, field: field, location: schema.location + "/" + keyword, message: message})
+}
+
+// evaluateField applies the schema to a field of the object. A field that is not allowed at all is reported
+// as an unexpected field, like the [comparator.Comparator] does.
+func (evaluator *evaluator) evaluateField(fieldSchema *node, value any, path string, pointer string,
+	scope []*resource, result *result) {
+	if fieldSchema.isFalse() {
+		result.violations = append(result.violations, violation{kind: comparator.UnexpectedField, path: path,
+			pointer: pointer, location: fieldSchema.location, actual: value, message: "unexpected field"})
+		return
+	}
+	fieldResult := evaluator.evaluate(fieldSchema, value, path, pointer, scope)
+	result.violations = append(result.violations, fieldResult.violations...)
+}
+
+// evaluateApplicators applies the subschemas that validate the same value, like 'allOf' or 'if'.
+func (evaluator *evaluator) evaluateApplicators(schema *node, value any, path string, pointer string,
+	scope []*resource, result *result) {
+	for _, subschema := range schema.allOf {
+		result.merge(evaluator.evaluate(subschema, value, path, pointer, scope))
+	}
+
+	if schema.anyOf != nil {
+		results := evaluator.evaluateAll(schema.anyOf, value, path, pointer, scope)
+		matching := validIndexes(results)
+		for _, i := range matching {
+			result.mergeAnnotations(results[i])
+		}
+		if len(matching) == 0 {
+			result.fail(schema, "anyOf", comparator.ValueMismatch, path, pointer, nil, value,
+				"value mismatch - expected to match at least one schema of [anyOf] but matched none - %s",
+				summarize(results, path))
+		}
+	}
+
+	if schema.oneOf != nil {
+		results := evaluator.evaluateAll(schema.oneOf, value, path, pointer, scope)
+		matching := validIndexes(results)
+		switch len(matching) {
+		case 0:
+			result.fail(schema, "oneOf", comparator.ValueMismatch, path, pointer, nil, value,
+				"value mismatch - expected to match exactly one schema of [oneOf] but matched none - %s",
+				summarize(results, path))
+		case 1:
+			result.mergeAnnotations(results[matching[0]])
+		default:
+			result.fail(schema, "oneOf", comparator.ValueMismatch, path, pointer, nil, value,
+				"value mismatch - expected to match exactly one schema of [oneOf] but matched %v", matching)
+		}
+	}
+
+	if schema.not != nil && evaluator.evaluate(schema.not, value, path, pointer, scope).valid() {
+		result.fail(schema, "not", comparator.ValueMismatch, path, pointer, nil, value,
+			"value mismatch - expected not to match the schema of [not]")
+	}
+
+	if schema.ifSchema != nil {
+		if ifResult := evaluator.evaluate(schema.ifSchema, value, path, pointer, scope); ifResult.valid() {
+			result.mergeAnnotations(ifResult)
+			if schema.thenSchema != nil {
+				result.merge(evaluator.evaluate(schema.thenSchema, value, path, pointer, scope))
+			}
+		} else if schema.elseSchema != nil {
+			result.merge(evaluator.evaluate(schema.elseSchema, value, path, pointer, scope))
+		}
+	}
+
+	if object, isObject := value.(map[string]any); isObject {
+		for _, field := range sortedKeys(schema.dependentSchemas) {
+			if _, exists := object[field]; exists {
+				result.merge(evaluator.evaluate(schema.dependentSchemas[field], value, path, pointer, scope))
+			}
+		}
+	}
+}
+
+func (evaluator *evaluator) evaluateAll(schemas []*node, value any, path string, pointer string,
+	scope []*resource) []result {
+	results := make([]result, 0, len(schemas))
+	for _, subschema := range schemas {
+		results = append(results, evaluator.evaluate(subschema, value, path, pointer, scope))
+	}
+	return results
+}
+
+func validIndexes(results []result) []int {
+	var indexes []int
+	for i, subschemaResult := range results {
+		if subschemaResult.valid() {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// summarize describes why each subschema failed with its first violation, since the violations of the subschemas
+// are not reported themselves.
+func summarize(results []result, path string) string {
+	summaries := make([]string, 0, len(results))
+	for i, subschemaResult := range results {
+		if len(subschemaResult.violations) == 0 {
+			continue
+		}
+
+		first := subschemaResult.violations[0]
+		if first.path != path {
+			summaries = append(summaries, fmt.Sprintf("[%d] [%s] %s", i, first.path, first.message))
+		} else {
+			summaries = append(summaries, fmt.Sprintf("[%d] %s", i, first.message))
+		}
+	}
+	return strings.Join(summaries, "; ")
+}
+
+func (evaluator *evaluator) evaluateUnevaluatedItems(schema *node, array []any, path string, pointer string,
+	scope []*resource, result *result) {
+	if schema.unevaluatedItems == nil || result.allItems {
+		return
+	}
+
+	for i := result.items; i < len(array); i++ {
+		if !result.containsItems[i] {
+			evaluator.evaluateItem(schema.unevaluatedItems, array, i, path, pointer, scope, result)
+		}
+	}
+	result.allItems = true
+}
+
+func (evaluator *evaluator) evaluateUnevaluatedProperties(schema *node, object map[string]any, path string,
+	pointer string, scope []*resource, result *result) {
+	if schema.unevaluatedProperties == nil {
+		return
+	}
+
+	for _, field := range sortedKeys(object) {
+		if !result.properties[field] {
+			evaluator.evaluateField(schema.unevaluatedProperties, object[field], jsonpath.GetObjectChildPath(path, field),
+				jsonpath.GetObjectChildPointer(pointer, field), scope, result)
+			result.addProperty(field)
+		}
+	}
+}
+
+func hasType(value any, jsonType string) bool {
+	if jsonType == "integer" {
+		number, isNumber := value.(json.Number)
+		return isNumber && exactValue(number).IsInt()
+	}
+	return typeOf(value) == jsonType
+}
+
+// typeOf returns the JSON type of a value created by a decoder with json.Number values.
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// isMultiple checks with exact decimal arithmetic, so that 0.3 is a multiple of 0.1.
+func isMultiple(value *big.Rat, divisor *big.Rat) bool {
+	return new(big.Rat).Quo(value, divisor).IsInt()
+}
+
+// equal compares two JSON values deeply. Numbers are compared by their exact value, so 1 and 1.0 are equal,
+// but two large integers that only differ after the precision of a float64 are not.
+func equal(left any, right any) bool {
+	switch typedLeft := left.(type) {
+	case json.Number:
+		typedRight, isNumber := right.(json.Number)
+		return isNumber && exactValue(typedLeft).Cmp(exactValue(typedRight)) == 0
+	case []any:
+		typedRight, isArray := right.([]any)
+		if !isArray || len(typedLeft) != len(typedRight) {
+			return false
+		}
+		for i := range typedLeft {
+			if !equal(typedLeft[i], typedRight[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		typedRight, isObject := right.(map[string]any)
+		if !isObject || len(typedLeft) != len(typedRight) {
+			return false
+		}
+		for key, value := range typedLeft {
+			if rightValue, exists := typedRight[key]; !exists || !equal(value, rightValue) {
+				return false
+			}
+		}
+		return true
+	default:
+		return left == right
+	}
+}
+
+// exactValue returns the value of a number that was checked by checkNumbers when it was parsed.
+func exactValue(number json.Number) *big.Rat {
+	value, _ := parseNumber(number)
+	return value
+}
+
+// parseNumber returns the exact value of a number. The exponent is limited, since the size of the value grows
+// with it; larger exponents are outside the range of a float64 anyway.
+func parseNumber(number json.Number) (*big.Rat, error) {
+	text := string(number)
+	if index := strings.IndexAny(text, "eE"); index >= 0 {
+		if exponent, err := strconv.Atoi(text[index+1:]); err != nil || exponent > maxExponent || exponent < -maxExponent {
+			return nil, fmt.Errorf("number [%s] is out of range", text)
+		}
+	}
+
+	value, isValid := new(big.Rat).SetString(text)
+	if !isValid {
+		return nil, fmt.Errorf("invalid number [%s]", text)
+	}
+	return value, nil
+}
+
+// checkNumbers checks that all numbers of a parsed JSON can be compared exactly.
+func checkNumbers(value any) error {
+	switch typedValue := value.(type) {
+	case json.Number:
+		_, err := parseNumber(typedValue)
+		return err
+	case []any:
+		for _, item := range typedValue {
+			if err := checkNumbers(item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, fieldValue := range typedValue {
+			if err := checkNumbers(fieldValue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatValue returns the representation of a value used in error messages, the same as the one of the
+// [comparator.Comparator] for strings, numbers & booleans.
+func formatValue(value any) string {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue
+	case json.Number:
+		return typedValue.String()
+	case bool:
+		return strconv.FormatBool(typedValue)
+	case nil:
+		return "null"
+	default:
+		formatted, err := json.Marshal(typedValue)
+		if err != nil {
+			return fmt.Sprintf("%v", typedValue)
+		}
+		return string(formatted)
+	}
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,207 @@
+package schema
+
+import (
+	"encoding/json"
+	"github.com/go-clarum/clarum-json/comparator"
+	"testing"
+)
+
+func TestKeywords(t *testing.T) {
+	testCases := []struct {
+		name           string
+		schema         string
+		actual         string
+		expectedErrors []string
+	}{
+		{"type", `{"type": "string"}`, `"Bruce"`, nil},
+		{"type mismatch", `{"type": ["string", "null"]}`, `1`,
+			[]string{"[$] - type mismatch - expected [string, null] but found [number] - schema [#/type]"}},
+		{"integer", `{"type": "integer"}`, `1.0`, nil},
+		{"not an integer", `{"type": "integer"}`, `1.5`,
+			[]string{"[$] - type mismatch - expected [integer] but found [number] - schema [#/type]"}},
+		{"const", `{"const": {"name": "Bruce"}}`, `{"name": "Bruce"}`, nil},
+		{"const mismatch", `{"const": "Bruce"}`, `"Dick"`,
+			[]string{"[$] - value mismatch - expected [Bruce] but received [Dick] - schema [#/const]"}},
+		{"enum", `{"enum": ["Bruce", 1, null]}`, `null`, nil},
+		{"enum mismatch", `{"enum": ["Bruce", [1]]}`, `"Dick"`,
+			[]string{"[$] - value mismatch - expected one of [Bruce, [1]] but received [Dick] - schema [#/enum]"}},
+		{"multipleOf", `{"multipleOf": 0.1}`, `0.3`, nil},
+		{"multipleOf mismatch", `{"multipleOf": 2}`, `7`,
+			[]string{"[$] - value mismatch - expected a multiple of [2] but received [7] - schema [#/multipleOf]"}},
+		{"exact numbers", `{"const": 9007199254740993, "enum": [1.0, 9007199254740993], "minimum": 9007199254740993}`,
+			`9007199254740993`, nil},
+		{"exact number mismatches", `{"const": 9007199254740993, "maximum": 9007199254740992}`, `9007199254740992.5`,
+			[]string{
+				"[$] - value mismatch - expected [9007199254740993] but received [9007199254740992.5] - schema [#/const]",
+				"[$] - value mismatch - expected a value <= [9007199254740992] but received [9007199254740992.5] - schema [#/maximum]",
+			}},
+		{"limits", `{"minimum": 1, "maximum": 3, "exclusiveMinimum": 0, "exclusiveMaximum": 4}`, `3`, nil},
+		{"limit mismatches", `{"minimum": 5, "maximum": 1, "exclusiveMinimum": 3, "exclusiveMaximum": 3}`, `3`,
+			[]string{
+				"[$] - value mismatch - expected a value <= [1] but received [3] - schema [#/maximum]",
+				"[$] - value mismatch - expected a value < [3] but received [3] - schema [#/exclusiveMaximum]",
+				"[$] - value mismatch - expected a value >= [5] but received [3] - schema [#/minimum]",
+				"[$] - value mismatch - expected a value > [3] but received [3] - schema [#/exclusiveMinimum]",
+			}},
+		{"string length", `{"minLength": 2, "maxLength": 2}`, `"äö"`, nil},
+		{"string length mismatches", `{"minLength": 3, "maxLength": 1}`, `"äö"`,
+			[]string{
+				"[$] - value mismatch - expected at most [1] character(s) but received [2] - schema [#/maxLength]",
+				"[$] - value mismatch - expected at least [3] character(s) but received [2] - schema [#/minLength]",
+			}},
+		{"pattern", `{"pattern": "^[A-Z]"}`, `"Bruce"`, nil},
+		{"pattern mismatch", `{"pattern": "^[A-Z]"}`, `"bruce"`,
+			[]string{"[$] - value mismatch - expected to match [^[A-Z]] but received [bruce] - schema [#/pattern]"}},
+		{"keywords of other types", `{"minLength": 5, "minimum": 5, "required": ["id"], "minItems": 5}`, `true`, nil},
+		{"array size", `{"minItems": 3, "maxItems": 1}`, `[1, 2]`,
+			[]string{
+				"[$] - array size mismatch - expected at most [1] item(s) but received [2] - schema [#/maxItems]",
+				"[$] - array size mismatch - expected at least [3] item(s) but received [2] - schema [#/minItems]",
+			}},
+		{"unique numbers", `{"uniqueItems": true}`, `[9007199254740992, 9007199254740993]`, nil},
+		{"equal numbers", `{"uniqueItems": true}`, `[1, 1.0]`,
+			[]string{"[$] - value mismatch - expected unique items but items [0] and [1] are equal - schema [#/uniqueItems]"}},
+		{"unique items", `{"uniqueItems": true}`, `[1, {"a": 1}, {"a": 1}]`,
+			[]string{"[$] - value mismatch - expected unique items but items [1] and [2] are equal - schema [#/uniqueItems]"}},
+		{"items", `{"prefixItems": [{"type": "string"}], "items": {"type": "number"}}`, `["a", 1, "b"]`,
+			[]string{"[$[2]] - type mismatch - expected [number] but found [string] - schema [#/items/type]"}},
+		{"no additional items", `{"prefixItems": [true], "items": false}`, `[1, 2]`,
+			[]string{"[$[1]] - unexpected item - schema [#/items]"}},
+		{"contains", `{"contains": {"type": "string"}, "minContains": 2, "maxContains": 2}`, `["a", 1, "b"]`, nil},
+		{"contains mismatch", `{"contains": {"type": "string"}}`, `[1]`,
+			[]string{"[$] - value mismatch - expected at least [1] item(s) matching [contains] but received [0] - schema [#/contains]"}},
+		{"maxContains mismatch", `{"contains": {"type": "string"}, "maxContains": 1}`, `["a", "b"]`,
+			[]string{"[$] - value mismatch - expected at most [1] item(s) matching [contains] but received [2] - schema [#/maxContains]"}},
+		{"minContains zero", `{"contains": {"type": "string"}, "minContains": 0}`, `[1]`, nil},
+		{"field count", `{"minProperties": 2, "maxProperties": 0}`, `{"a": 1}`,
+			[]string{
+				"[$] - field count mismatch - expected at most [0] field(s) but received [1] - schema [#/maxProperties]",
+				"[$] - field count mismatch - expected at least [2] field(s) but received [1] - schema [#/minProperties]",
+			}},
+		{"required", `{"required": ["id", "name"]}`, `{"id": 1}`,
+			[]string{"[$.name] - field is missing - schema [#/required]"}},
+		{"dependentRequired", `{"dependentRequired": {"street": ["city"]}}`, `{"street": "Mountain Drive"}`,
+			[]string{"[$.city] - field is missing - required by [street] - schema [#/dependentRequired/street]"}},
+		{"properties", `{"properties": {"id": {"type": "integer"}, "name": false}}`, `{"id": "1", "name": "Bruce"}`,
+			[]string{
+				"[$.id] - type mismatch - expected [integer] but found [string] - schema [#/properties/id/type]",
+				"[$.name] - unexpected field - schema [#/properties/name]",
+			}},
+		{"patternProperties", `{"patternProperties": {"^x-": {"type": "string"}}, "additionalProperties": false}`,
+			`{"x-trace": 1, "id": 1}`,
+			[]string{
+				"[$.id] - unexpected field - schema [#/additionalProperties]",
+				"[$['x-trace']] - type mismatch - expected [string] but found [number] - schema [#/patternProperties/^x-/type]",
+			}},
+		{"propertyNames", `{"propertyNames": {"maxLength": 3}}`, `{"id": 1, "name": "Bruce"}`,
+			[]string{"[$.name] - unexpected field - invalid name - value mismatch - expected at most [3] character(s) but received [4] - schema [#/propertyNames]"}},
+		{"allOf", `{"allOf": [{"type": "number"}, {"minimum": 2}]}`, `1`,
+			[]string{"[$] - value mismatch - expected a value >= [2] but received [1] - schema [#/allOf/1/minimum]"}},
+		{"anyOf", `{"anyOf": [{"type": "string"}, {"minimum": 2}]}`, `3`, nil},
+		{"anyOf mismatch", `{"anyOf": [{"type": "string"}, {"required": ["id"]}]}`, `{}`,
+			[]string{"[$] - value mismatch - expected to match at least one schema of [anyOf] but matched none - " +
+				"[0] type mismatch - expected [string] but found [object]; [1] [$.id] field is missing - schema [#/anyOf]"}},
+		{"oneOf", `{"oneOf": [{"type": "string"}, {"minimum": 2}]}`, `3`, nil},
+		{"oneOf with two matches", `{"oneOf": [{"type": "number"}, {"minimum": 2}]}`, `3`,
+			[]string{"[$] - value mismatch - expected to match exactly one schema of [oneOf] but matched [0 1] - schema [#/oneOf]"}},
+		{"not", `{"not": {"type": "string"}}`, `"Bruce"`,
+			[]string{"[$] - value mismatch - expected not to match the schema of [not] - schema [#/not]"}},
+		{"then", `{"if": {"required": ["street"]}, "then": {"required": ["city"]}, "else": false}`, `{"street": "a"}`,
+			[]string{"[$.city] - field is missing - schema [#/then/required]"}},
+		{"else", `{"if": {"required": ["street"]}, "then": {"required": ["city"]}, "else": false}`, `{}`,
+			[]string{"[$] - value is not allowed - schema [#/else]"}},
+		{"dependentSchemas", `{"dependentSchemas": {"street": {"required": ["city"]}}}`, `{"street": "a"}`,
+			[]string{"[$.city] - field is missing - schema [#/dependentSchemas/street/required]"}},
+		{"true & false", `{"properties": {"a": true, "b": {"not": true}}}`, `{"a": 1, "b": 2}`,
+			[]string{"[$.b] - value mismatch - expected not to match the schema of [not] - schema [#/properties/b/not]"}},
+		{"false", `false`, `{}`, []string{"[$] - value is not allowed - schema [#]"}},
+		{"unknown keywords", `{"format": "email", "x-owner": "team", "$comment": "annotations"}`, `"Bruce"`, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewValidator().Build().Validate([]byte(testCase.schema), []byte(testCase.actual))
+			checkErrors(t, err, testCase.expectedErrors)
+		})
+	}
+}
+
+func TestUnevaluated(t *testing.T) {
+	testCases := []struct {
+		name           string
+		schema         string
+		actual         string
+		expectedErrors []string
+	}{
+		{"properties of allOf", `{"allOf": [{"properties": {"id": true}}], "unevaluatedProperties": false}`,
+			`{"id": 1, "name": "Bruce"}`,
+			[]string{"[$.name] - unexpected field - schema [#/unevaluatedProperties]"}},
+		{"properties of a reference", `{"$ref": "#/$defs/base", "properties": {"name": true},
+			"unevaluatedProperties": false, "$defs": {"base": {"properties": {"id": true}}}}`,
+			`{"id": 1, "name": "Bruce"}`, nil},
+		{"properties of a failed subschema", `{"anyOf": [{"properties": {"id": {"type": "string"}}}, true],
+			"unevaluatedProperties": false}`, `{"id": 1}`,
+			[]string{"[$.id] - unexpected field - schema [#/unevaluatedProperties]"}},
+		{"properties of if & then", `{"if": {"properties": {"type": {"const": "car"}}},
+			"then": {"properties": {"wheels": true}}, "unevaluatedProperties": false}`,
+			`{"type": "car", "wheels": 4}`, nil},
+		{"unevaluated property schema", `{"properties": {"id": true}, "unevaluatedProperties": {"type": "string"}}`,
+			`{"id": 1, "name": 2}`,
+			[]string{"[$.name] - type mismatch - expected [string] but found [number] - schema [#/unevaluatedProperties/type]"}},
+		{"items", `{"allOf": [{"prefixItems": [true]}], "unevaluatedItems": false}`, `[1, 2]`,
+			[]string{"[$[1]] - unexpected item - schema [#/unevaluatedItems]"}},
+		{"contains", `{"contains": {"type": "string"}, "unevaluatedItems": false}`, `["a", 1]`,
+			[]string{"[$[1]] - unexpected item - schema [#/unevaluatedItems]"}},
+		{"all items", `{"items": true, "unevaluatedItems": false}`, `[1, 2]`, nil},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := NewValidator().Build().Validate([]byte(testCase.schema), []byte(testCase.actual))
+			checkErrors(t, err, testCase.expectedErrors)
+		})
+	}
+}
+
+func TestMismatchKinds(t *testing.T) {
+	schema := `{"type": "object", "required": ["id"], "additionalProperties": false, "maxProperties": 0,
+		"properties": {"tags": {"maxItems": 0}, "name": {"type": "string", "const": "Bruce"}}}`
+
+	_, err := NewValidator().Build().Validate([]byte(schema), []byte(`{"tags": [1], "name": "Dick", "age": 1}`))
+
+	kinds := make(map[comparator.MismatchKind]string)
+	for _, mismatch := range comparator.Mismatches(err) {
+		kinds[mismatch.Kind] = mismatch.Pointer
+	}
+	expectedKinds := map[comparator.MismatchKind]string{
+		comparator.FieldCountMismatch: "",
+		comparator.MissingField:       "/id",
+		comparator.UnexpectedField:    "/age",
+		comparator.ArraySizeMismatch:  "/tags",
+		comparator.ValueMismatch:      "/name",
+	}
+	for kind, pointer := range expectedKinds {
+		if actualPointer, exists := kinds[kind]; !exists || actualPointer != pointer {
+			t.Errorf("expected a [%s] mismatch at [%s]: %v", kind, pointer, err)
+		}
+	}
+}
+
+func TestIsMultiple(t *testing.T) {
+	for _, testCase := range []struct {
+		value    json.Number
+		divisor  json.Number
+		multiple bool
+	}{
+		{"0.3", "0.1", true},
+		{"4.5", "1.5", true},
+		{"1e308", "1e-308", true},
+		{"0.31", "0.1", false},
+		{"7", "2", false},
+		{"9007199254740993", "2", false},
+	} {
+		if isMultiple(exactValue(testCase.value), exactValue(testCase.divisor)) != testCase.multiple {
+			t.Errorf("wrong result for [%v] & [%v]", testCase.value, testCase.divisor)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package schema
+
+import (
+	"github.com/go-clarum/clarum-json/internal"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"github.com/go-clarum/clarum-json/recorder"
+	"reflect"
+	"strings"
+)
+
+// schemaRecording walks over the actual JSON after the validation and reports it to the recorder the same way
+// the [comparator.Comparator] reports a comparison, with the violations at the values they were found at.
+// As there is no expected JSON, the actual values are passed as both expected & actual to a [recorder.PairRecorder].
+type schemaRecording struct {
+	recorder recorder.Recorder
+	// messages contains the messages of the violations by the pointer of the value
+	messages map[string][]string
+	// missingFields contains the names of the missing fields by the pointer of their object
+	missingFields map[string][]string
+}
+
+func record(rec recorder.Recorder, actual any, violations []violation) {
+	recording := &schemaRecording{recorder: rec, messages: make(map[string][]string),
+		missingFields: make(map[string][]string)}
+	for _, violation := range violations {
+		if violation.field != "" {
+			recording.missingFields[violation.parentPointer] = append(recording.missingFields[violation.parentPointer],
+				violation.field)
+		} else {
+			recording.messages[violation.pointer] = append(recording.messages[violation.pointer], violation.message)
+		}
+	}
+
+	switch typedValue := actual.(type) {
+	case map[string]any:
+		recording.recordObject(jsonpath.RootPath, jsonpath.RootPointer, "", typedValue, "")
+	case []any:
+		recording.recordArray(jsonpath.RootPath, jsonpath.RootPointer, "", typedValue, "")
+	default:
+		recording.recordValue(jsonpath.RootPath, jsonpath.RootPointer, "", typedValue, "")
+	}
+}
+
+func (recording *schemaRecording) message(pointer string) string {
+	return strings.Join(recording.messages[pointer], "; ")
+}
+
+func (recording *schemaRecording) recordObject(path string, pointer string, fieldName string, object map[string]any,
+	logIndent string) {
+	currIndent := logIndent + "  "
+
+	recording.recorder.AppendStartObject(logIndent, path)
+	message := recording.message(pointer)
+	recording.signal(message)
+	internal.RecordPairStart(recording.recorder, path, fieldName, reflect.Map, message)
+
+	for _, key := range sortedKeys(object) {
+		childPath, childPointer := jsonpath.GetObjectChildPath(path, key), jsonpath.GetObjectChildPointer(pointer, key)
+		recording.recorder.AppendFieldName(currIndent, key)
+
+		switch typedValue := object[key].(type) {
+		case map[string]any:
+			recording.recordObject(childPath, childPointer, key, typedValue, currIndent)
+		case []any:
+			recording.recordArray(childPath, childPointer, key, typedValue, currIndent)
+		default:
+			recording.recordValue(childPath, childPointer, key, typedValue, logIndent)
+		}
+	}
+
+	for _, field := range recording.missingFields[pointer] {
+		recording.recorder.AppendMissingFieldErrorSignal(currIndent, field)
+		internal.RecordMissingPair(recording.recorder, jsonpath.GetObjectChildPath(path, field), field, nil)
+	}
+
+	recording.recorder.AppendEndObject(logIndent, path)
+	internal.RecordPairEnd(recording.recorder, path, reflect.Map)
+}
+
+func (recording *schemaRecording) recordArray(path string, pointer string, fieldName string, array []any,
+	currIndent string) {
+	recording.recorder.AppendStartArray(currIndent, path)
+	message := recording.message(pointer)
+	recording.signal(message)
+	internal.RecordPairStart(recording.recorder, path, fieldName, reflect.Slice, message)
+
+	valIndent := currIndent + "  "
+	for i, item := range array {
+		itemPath, itemPointer := jsonpath.GetArrayIndexPath(path, i), jsonpath.GetArrayIndexPointer(pointer, i)
+
+		switch typedValue := item.(type) {
+		case map[string]any:
+			recording.recordObject(itemPath, itemPointer, "", typedValue, valIndent)
+		case []any:
+			recording.recordArray(itemPath, itemPointer, "", typedValue, valIndent)
+		default:
+			recording.recordValue(itemPath, itemPointer, "", typedValue, valIndent)
+		}
+	}
+
+	recording.recorder.AppendEndArray(currIndent, path)
+	internal.RecordPairEnd(recording.recorder, path, reflect.Slice)
+}
+
+func (recording *schemaRecording) recordValue(path string, pointer string, fieldName string, value any, indent string) {
+	recording.recorder.AppendValue(indent, path, formatValue(value), reflect.String)
+	message := recording.message(pointer)
+	recording.signal(message)
+	internal.RecordPair(recording.recorder, path, fieldName, value, value, message)
+}
+
+func (recording *schemaRecording) signal(message string) {
+	if message != "" {
+		recording.recorder.AppendValidationErrorSignal(message)
+	} else {
+		recording.recorder.AppendNewLine()
+	}
+}
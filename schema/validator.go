@@ -0,0 +1,133 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-json/jsonpath"
+)
+
+// Validator validates JSON documents against a JSON Schema, draft 2020-12 with the core, applicator & validation
+// vocabularies. References are resolved within the schema, by JSON Pointer, '$anchor', '$dynamicAnchor' or the '$id'
+// of embedded schemas; remote references are not supported. The 'format' keyword is only an annotation, as the draft
+// defines by default, and the patterns use the syntax of the Go regexp package. Numbers are compared by their exact
+// decimal value, not as float64.
+//
+// The violations are returned as [comparator.Mismatch] errors, with the same paths & kinds as the mismatches of
+// a [comparator.Comparator], so that schema & example checks can be reported together. For example, a required field
+// that is missing is a [comparator.MissingField] and a field forbidden by 'additionalProperties' is
+// a [comparator.UnexpectedField]. The message ends with the location of the keyword in the schema.
+//
+// Always create a validator using the [NewValidator] builder.
+type Validator struct {
+	options
+}
+
+// Validate validates the actual JSON against the schema and returns the recorder output and the violations
+// joined into one error, nil if the JSON is valid.
+func (validator *Validator) Validate(schema []byte, actual []byte) (string, error) {
+	validator.logger.Debug(fmt.Sprintf("json schema validator - validating [%s] against [%s]", actual, schema))
+
+	compiled, err := validator.Compile(schema)
+	if err != nil {
+		return "", err
+	}
+
+	actualJsonObject, err := unmarshalJson(actual)
+	if err != nil {
+		return "", err
+	}
+
+	violations, err := compiled.evaluate(actualJsonObject)
+	if err != nil {
+		return "", err
+	}
+
+	if len(violations) > 0 {
+		validator.logger.Debug("json schema validator - JSON is not valid")
+	} else {
+		validator.logger.Debug("json schema validator - JSON is valid")
+	}
+
+	record(validator.recorder, actualJsonObject, violations)
+	return validator.recorder.GetLog(), joinViolations(violations)
+}
+
+// Compile parses the schema and resolves its references once, so that it can validate many JSONs.
+// The recorder of the validator is not used by the [Schema], since the output of concurrent validations
+// would be mixed up.
+func (validator *Validator) Compile(schema []byte) (*Schema, error) {
+	document, err := decodeJson(schema)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse schema - error [%s]", err)
+	}
+
+	root, err := compile(document)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{root}, nil
+}
+
+// Schema is a JSON Schema compiled by [Validator.Compile].
+//
+// A Schema is immutable and goroutine safe.
+type Schema struct {
+	root *node
+}
+
+// Validate validates the actual JSON and returns the same errors as [Validator.Validate].
+func (schema *Schema) Validate(actual []byte) error {
+	actualJsonObject, err := unmarshalJson(actual)
+	if err != nil {
+		return err
+	}
+
+	violations, err := schema.evaluate(actualJsonObject)
+	if err != nil {
+		return err
+	}
+	return joinViolations(violations)
+}
+
+func (schema *Schema) evaluate(actual any) ([]violation, error) {
+	evaluator := &evaluator{}
+	evaluation := evaluator.evaluate(schema.root, actual, jsonpath.RootPath, jsonpath.RootPointer, nil)
+	return evaluation.violations, evaluator.err
+}
+
+func joinViolations(violations []violation) error {
+	mismatches := make([]error, 0, len(violations))
+	for _, violation := range violations {
+		mismatches = append(mismatches, violation.mismatch())
+	}
+	return errors.Join(mismatches...)
+}
+
+func unmarshalJson(rawJson []byte) (any, error) {
+	result, err := decodeJson(rawJson)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse JSON - error [%s] - from string [%s]", err, rawJson)
+	}
+
+	return result, nil
+}
+
+// decodeJson parses the JSON with json.Number values, so that the numbers of the schema & the actual JSON
+// are compared exactly instead of as float64.
+// We rely on json.Unmarshal to detect invalid json structures here, since the decoder reports them differently.
+func decodeJson(rawJson []byte) (any, error) {
+	if err := json.Unmarshal(rawJson, new(json.RawMessage)); err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawJson))
+	decoder.UseNumber()
+
+	var result any
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+	return result, checkNumbers(result)
+}
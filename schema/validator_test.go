@@ -0,0 +1,156 @@
+package schema
+
+import (
+	"github.com/go-clarum/clarum-json/comparator"
+	"github.com/go-clarum/clarum-json/recorder"
+	"github.com/go-clarum/clarum-json/report"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const customerSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["id", "name"],
+  "properties": {
+    "id": {"type": "integer"},
+    "name": {"type": "string", "minLength": 1},
+    "aliases": {"type": "array", "items": {"type": "string"}}
+  },
+  "additionalProperties": false
+}`
+
+func TestValidate(t *testing.T) {
+	validator := NewValidator().Recorder(recorder.NewDefaultRecorder()).Build()
+
+	recorderLog, err := validator.Validate([]byte(customerSchema),
+		[]byte(`{"id": 1.5, "aliases": ["Batman", 1], "city": "Gotham"}`))
+
+	checkErrors(t, err, []string{
+		"[$.name] - field is missing - schema [#/required]",
+		"[$.aliases[1]] - type mismatch - expected [string] but found [number] - schema [#/properties/aliases/items/type]",
+		"[$.city] - unexpected field - schema [#/additionalProperties]",
+		"[$.id] - type mismatch - expected [integer] but found [number] - schema [#/properties/id/type]",
+	})
+	checkRecorderLog(t, "{\n"+
+		"  \"aliases\": [\n"+
+		"    Batman,\n"+
+		"    1, <-- type mismatch - expected [string] but found [number]\n"+
+		"  ],\n"+
+		"  \"city\": Gotham, <-- unexpected field\n"+
+		"  \"id\": 1.5, <-- type mismatch - expected [integer] but found [number]\n"+
+		"   X-- missing field [name]\n"+
+		"}\n", recorderLog)
+}
+
+func TestValidateValid(t *testing.T) {
+	validator := NewValidator().Recorder(recorder.NewDefaultRecorder()).Build()
+
+	recorderLog, err := validator.Validate([]byte(customerSchema), []byte(`{"id": 1, "name": "Bruce"}`))
+
+	checkErrors(t, err, nil)
+	checkRecorderLog(t, "{\n  \"id\": 1,\n  \"name\": Bruce,\n}\n", recorderLog)
+}
+
+func TestValidateRoot(t *testing.T) {
+	validator := NewValidator().Recorder(recorder.NewDefaultRecorder()).Build()
+
+	recorderLog, err := validator.Validate([]byte(`{"type": "array", "maxItems": 1}`), []byte(`[1, [2]]`))
+
+	checkErrors(t, err, []string{
+		"[$] - array size mismatch - expected at most [1] item(s) but received [2] - schema [#/maxItems]",
+	})
+	checkRecorderLog(t, "[ <-- array size mismatch - expected at most [1] item(s) but received [2]\n"+
+		"  1,\n"+
+		"  [\n"+
+		"    2,\n"+
+		"  ],\n"+
+		"]\n", recorderLog)
+}
+
+func TestValidateSideBySide(t *testing.T) {
+	validator := NewValidator().Recorder(recorder.NewSideBySideRecorder()).Build()
+
+	recorderLog, _ := validator.Validate([]byte(customerSchema), []byte(`{"id": "1", "name": "Bruce"}`))
+
+	expected := "{                    {\n" +
+		"  \"id\": \"1\",       |   \"id\": \"1\",\n" +
+		"  \"name\": \"Bruce\",     \"name\": \"Bruce\",\n" +
+		"}                    }\n"
+	checkRecorderLog(t, expected, recorderLog)
+}
+
+func TestValidateInvalidJson(t *testing.T) {
+	_, err := NewValidator().Build().Validate([]byte(customerSchema), []byte(`{"id": `))
+
+	if err == nil || err.Error() != "unable to parse JSON - error [unexpected end of JSON input] - from string [{\"id\": ]" {
+		t.Errorf("wrong error: %v", err)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	schema, err := NewValidator().Recorder(recorder.NewDefaultRecorder()).Build().Compile([]byte(customerSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wait sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			if i%2 == 0 {
+				checkErrors(t, schema.Validate([]byte(`{"id": 1, "name": "Bruce"}`)), nil)
+			} else {
+				checkErrors(t, schema.Validate([]byte(`{"id": 1}`)), []string{"[$.name] - field is missing - schema [#/required]"})
+			}
+		}(i)
+	}
+	wait.Wait()
+}
+
+func TestReportWithComparison(t *testing.T) {
+	_, schemaErr := NewValidator().Build().Validate([]byte(customerSchema), []byte(`{"id": 1}`))
+	_, compareErr := comparator.NewComparator().Build().Compare([]byte(`{"name": "Bruce"}`), []byte(`{"name": "Dick"}`))
+
+	suite := report.Suite{Name: "customers", Results: []report.Result{
+		{Name: "schema", Err: schemaErr},
+		{Name: "example", Err: compareErr},
+	}}
+
+	if _, failed, _ := suite.Counts(); failed != 2 {
+		t.Errorf("both results must fail: %+v", suite)
+	}
+	if mismatches := suite.Results[0].Mismatches(); len(mismatches) != 1 ||
+		mismatches[0].Kind != comparator.MissingField || mismatches[0].Path != "$.name" {
+		t.Errorf("wrong mismatches: %v", schemaErr)
+	}
+}
+
+// checkErrors checks that the error contains exactly the expected messages, in any order.
+func checkErrors(t *testing.T, err error, expectedErrors []string) {
+	t.Helper()
+
+	var actualErrors []string
+	if err != nil {
+		actualErrors = strings.Split(err.Error(), "\n")
+	}
+
+	if len(actualErrors) != len(expectedErrors) {
+		t.Errorf("expected [%d] error(s) but received [%d]:\n%v", len(expectedErrors), len(actualErrors), err)
+		return
+	}
+	for _, expected := range expectedErrors {
+		if !strings.Contains("\n"+err.Error()+"\n", "\n"+expected+"\n") {
+			t.Errorf("missing error [%s] in:\n%v", expected, err)
+		}
+	}
+}
+
+func checkRecorderLog(t *testing.T, expected string, actual string) {
+	t.Helper()
+	if expected != actual {
+		t.Errorf("wrong recorder log\nexpected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
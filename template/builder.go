@@ -0,0 +1,36 @@
+package template
+
+type Builder struct {
+	options
+}
+
+// NewGenerator is the builder initiator. Always use the builder to create a [Generator]
+// as this will set the default options.
+func NewGenerator() *Builder {
+	return &Builder{
+		options{
+			detectVolatileValues: true,
+		},
+	}
+}
+
+// DetectVolatileValues determines if the [Generator] replaces the strings that look volatile:
+// UUIDs, timestamps & random tokens.
+//
+// Default is 'true'.
+func (builder *Builder) DetectVolatileValues(detect bool) *Builder {
+	builder.detectVolatileValues = detect
+	return builder
+}
+
+// IgnorePaths is a list of JSONPath queries (RFC 9535) whose values are always replaced, for example
+// generated numbers like '$.id' or '$.items[*].createdAt'.
+// Default is empty.
+func (builder *Builder) IgnorePaths(paths ...string) *Builder {
+	builder.ignorePaths = append(builder.ignorePaths, paths...)
+	return builder
+}
+
+func (builder *Builder) Build() *Generator {
+	return &Generator{options: builder.options}
+}
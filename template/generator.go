@@ -0,0 +1,150 @@
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-json/internal/matcher"
+	"github.com/go-clarum/clarum-json/jsonpath"
+	"math"
+	"regexp"
+	"time"
+)
+
+// Generator creates expected JSONs for the comparator from actual ones, like a recorded response,
+// by replacing the values that change from one run to the next with the '@ignore@' marker.
+// The comparator has no type matchers, so the marker is used for all replaced values.
+//
+// A Generator is immutable and goroutine safe.
+type Generator struct {
+	options
+}
+
+type options struct {
+	detectVolatileValues bool
+	ignorePaths          []string
+}
+
+// minTokenLength is the minimum length of a string to be considered a random token.
+const minTokenLength = 16
+
+// minTokenEntropy is the minimum Shannon entropy, in bits per character, of a random token.
+const minTokenEntropy = 3.5
+
+var (
+	uuidPattern   = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	tokenPattern  = regexp.MustCompile(`^[A-Za-z0-9_\-+/=.]+$`)
+	digitPattern  = regexp.MustCompile(`[0-9]`)
+	letterPattern = regexp.MustCompile(`[A-Za-z]`)
+
+	timestampLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+		time.RFC1123,
+		time.RFC1123Z,
+	}
+)
+
+// Generate returns the template created from the actual JSON, indented with 2 spaces and with the object fields
+// sorted by name. Numbers are written exactly as they are in the actual JSON.
+func (generator *Generator) Generate(actual []byte) ([]byte, error) {
+	var actualJsonObject any
+	if err := json.Unmarshal(actual, &actualJsonObject); err != nil {
+		return nil, fmt.Errorf("unable to parse actual JSON - error [%s]", err)
+	}
+
+	ignoredPaths := make(map[string]bool)
+	for _, expression := range generator.ignorePaths {
+		nodes, err := jsonpath.Select(expression, actualJsonObject)
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			ignoredPaths[node.Path] = true
+		}
+	}
+
+	// the paths are selected on the standard representation, which the JSONPath filters expect,
+	// but the template is written from a copy that keeps the numbers unchanged
+	decoder := json.NewDecoder(bytes.NewReader(actual))
+	decoder.UseNumber()
+	var templateJsonObject any
+	if err := decoder.Decode(&templateJsonObject); err != nil {
+		return nil, fmt.Errorf("unable to parse actual JSON - error [%s]", err)
+	}
+
+	var template bytes.Buffer
+	encoder := json.NewEncoder(&template)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(generator.replace(jsonpath.RootPath, templateJsonObject, ignoredPaths)); err != nil {
+		return nil, err
+	}
+	return template.Bytes(), nil
+}
+
+func (generator *Generator) replace(path string, value any, ignoredPaths map[string]bool) any {
+	if ignoredPaths[path] {
+		return matcher.Ignore
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]any:
+		for key, fieldValue := range typedValue {
+			typedValue[key] = generator.replace(jsonpath.GetObjectChildPath(path, key), fieldValue, ignoredPaths)
+		}
+	case []any:
+		for i, itemValue := range typedValue {
+			typedValue[i] = generator.replace(jsonpath.GetArrayIndexPath(path, i), itemValue, ignoredPaths)
+		}
+	case string:
+		if generator.detectVolatileValues && IsVolatile(typedValue) {
+			return matcher.Ignore
+		}
+	}
+	return value
+}
+
+// IsVolatile checks if the string looks like a value that changes from one run to the next:
+// a UUID, a timestamp or a random token like an id, a hash or an access token.
+// Tokens are detected with a heuristic: they have at least 16 characters without spaces, mix letters & digits
+// and have a high entropy.
+func IsVolatile(value string) bool {
+	return isUuid(value) || isTimestamp(value) || isToken(value)
+}
+
+func isUuid(value string) bool {
+	return uuidPattern.MatchString(value)
+}
+
+func isTimestamp(value string) bool {
+	for _, layout := range timestampLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func isToken(value string) bool {
+	return len(value) >= minTokenLength && tokenPattern.MatchString(value) &&
+		digitPattern.MatchString(value) && letterPattern.MatchString(value) && entropy(value) >= minTokenEntropy
+}
+
+// entropy returns the Shannon entropy of the string in bits per character.
+func entropy(value string) float64 {
+	counts := make(map[rune]int)
+	for _, char := range value {
+		counts[char]++
+	}
+
+	length := float64(len(value))
+	result := 0.0
+	for _, count := range counts {
+		probability := float64(count) / length
+		result -= probability * math.Log2(probability)
+	}
+	return result
+}